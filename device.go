@@ -0,0 +1,78 @@
+package useragent
+
+// Device classes returned by DeviceClass and stored in EmulatedDevice
+const (
+	DeviceBot      = "bot"
+	DeviceConsole  = "console"
+	DeviceTV       = "tv"
+	DeviceWatch    = "watch"
+	DeviceTablet   = "tablet"
+	DeviceMobile   = "mobile"
+	DeviceDesktop  = "desktop"
+	DeviceChromeOS = "chromeos"
+)
+
+// DeviceClass returns top-level device category for analytics.
+// Precedence is bot > console > tv > watch > tablet > mobile > desktop > chromeos.
+// Bots are always reported as bot even if they emulate a phone,
+// see EmulatedDevice for the device a bot pretends to be.
+// ChromeOS is reported as chromeos if the parser was created with WithChromeOSAsDesktop(false).
+// Empty string is returned when the device is unknown.
+func (ua UserAgent) DeviceClass() string {
+	switch {
+	case ua.Bot:
+		return DeviceBot
+	case ua.Console:
+		return DeviceConsole
+	case ua.TV:
+		return DeviceTV
+	case ua.Watch:
+		return DeviceWatch
+	case ua.Tablet:
+		return DeviceTablet
+	case ua.Mobile:
+		return DeviceMobile
+	case ua.Desktop:
+		return DeviceDesktop
+	case ua.OS == ChromeOS:
+		return DeviceChromeOS
+	}
+	return ""
+}
+
+// emulatedDevice returns device class a bot pretends to be,
+// crawlers without a device hint are assumed to render as desktop.
+func emulatedDevice(ua *UserAgent) string {
+	switch {
+	case !ua.Bot:
+		return ""
+	case ua.Tablet:
+		return DeviceTablet
+	case ua.Mobile:
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
+
+// DeviceType is a single device category of a user agent returned by UserAgent.DeviceType.
+type DeviceType string
+
+// Device types returned by DeviceType, they match device classes returned by DeviceClass
+const (
+	DeviceTypeUnknown  DeviceType = ""
+	DeviceTypeBot      DeviceType = DeviceBot
+	DeviceTypeConsole  DeviceType = DeviceConsole
+	DeviceTypeTV       DeviceType = DeviceTV
+	DeviceTypeWatch    DeviceType = DeviceWatch
+	DeviceTypeTablet   DeviceType = DeviceTablet
+	DeviceTypeMobile   DeviceType = DeviceMobile
+	DeviceTypeDesktop  DeviceType = DeviceDesktop
+	DeviceTypeChromeOS DeviceType = DeviceChromeOS
+)
+
+// DeviceType is DeviceClass as a typed value so callers can switch on it,
+// e.g. a mobile crawler is DeviceTypeBot.
+// DeviceTypeUnknown is returned when the device is unknown.
+func (ua UserAgent) DeviceType() DeviceType {
+	return DeviceType(ua.DeviceClass())
+}
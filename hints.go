@@ -0,0 +1,156 @@
+package useragent
+
+import "strings"
+
+// ClientHints holds User-Agent Client Hints request headers.
+// Chrome reduced its user agent freezing minor version numbers,
+// the real ones are sent in the hints.
+type ClientHints struct {
+	// UA is Sec-CH-UA header, e.g. "Google Chrome";v="120", "Chromium";v="120", "Not_A Brand";v="8".
+	UA string
+	// FullVersionList is Sec-CH-UA-Full-Version-List header, e.g. "Google Chrome";v="120.0.6099.109".
+	FullVersionList string
+	// Mobile is Sec-CH-UA-Mobile header, ?1 for mobile and ?0 otherwise.
+	Mobile string
+	// Platform is Sec-CH-UA-Platform header, e.g. "Windows".
+	Platform string
+}
+
+// hintBrands maps client hints brands to browser names.
+var hintBrands = map[string]string{
+	"Google Chrome":    Chrome,
+	"Microsoft Edge":   Edge,
+	"Opera":            Opera,
+	"Brave":            Brave,
+	"Vivaldi":          Vivaldi,
+	"YaBrowser":        YandexBrowser,
+	"Whale":            Whale,
+	"Samsung Internet": SamsungBrowser,
+}
+
+// hintPlatforms maps Sec-CH-UA-Platform values to OS names.
+var hintPlatforms = map[string]string{
+	"Windows":   Windows,
+	"macOS":     MacOS,
+	"Android":   Android,
+	"iOS":       IOS,
+	"Linux":     Linux,
+	"Chrome OS": ChromeOS,
+}
+
+// ParseWithHints parses a user agent with Sec-CH-UA header value using the default parser.
+// It is safe to use concurrently.
+func ParseWithHints(userAgent, secCHUA string) UserAgent {
+	return defaultParser.ParseWithHints(userAgent, secCHUA)
+}
+
+// ParseClientHints parses a user agent refined with client hints using the default parser.
+// It is safe to use concurrently.
+func ParseClientHints(userAgent string, hints ClientHints) UserAgent {
+	return defaultParser.ParseClientHints(userAgent, hints)
+}
+
+// ParseWithHints parses a user agent refining the result with Sec-CH-UA client hints header,
+// e.g. "Brave";v="120", "Chromium";v="120", "Not_A Brand";v="24".
+// Brave sends the same user agent as Chrome and can be told apart only by its brand in the hint.
+// Empty secCHUA gives the same result as Parse.
+// It is safe to use concurrently.
+func (p *Parser) ParseWithHints(userAgent, secCHUA string) UserAgent {
+	return p.ParseClientHints(userAgent, ClientHints{UA: secCHUA})
+}
+
+// ParseClientHints parses a user agent refining the result with client hints
+// which take precedence when they are more precise than the user agent:
+// brand in Sec-CH-UA identifies Chromium based browsers, e.g. Brave,
+// Sec-CH-UA-Full-Version-List gives full version frozen in the user agent,
+// Sec-CH-UA-Mobile switches between mobile and desktop or tablet and Sec-CH-UA-Platform gives OS when user agent has none.
+// Zero hints give the same result as Parse.
+// It is safe to use concurrently.
+func (p *Parser) ParseClientHints(userAgent string, hints ClientHints) UserAgent {
+	var ua UserAgent
+	p.ParseInto(userAgent, &ua)
+
+	// hints are sent only by Chromium based browsers
+	if name := hintBrowser(hints.UA); name != "" && (ua.Name == Chrome || ua.Name == name) {
+		ua.Name = name
+	}
+	// non-numeric hint is ignored in strict mode keeping the version from the user agent
+	if ver := hintVersion(hints.FullVersionList, ua.Name); ver != "" && (!p.strictVersion || isNumericVersion(ver)) {
+		ua.Version = ver
+		ua.VersionNo = VersionNo{}
+		parseVersion(ua.Version, &ua.VersionNo)
+		if p.versionComponents > 0 && hasNumericMajor(ua.Version) {
+			ua.Version = formatVersion(ua.VersionNo, p.versionComponents)
+		}
+	}
+	ua.Outdated = isBelowMin(p.minVersions, ua.Name, ua.VersionNo)
+
+	// form factors exclude each other, TVs, consoles and watches keep their own
+	if (hints.Mobile == "?1" || hints.Mobile == "?0") && !ua.TV && !ua.Console && !ua.Watch {
+		ua.Mobile = hints.Mobile == "?1"
+		if ua.Mobile {
+			ua.Tablet = false
+			ua.Desktop = false
+		} else {
+			// Chrome sends ?0 on tablets and when desktop site is requested on a phone
+			ua.Desktop = !ua.Tablet && (ua.OS != ChromeOS || p.chromeOSAsDesktop)
+		}
+		ua.ScreenClass = screenClass(&ua)
+		ua.EmulatedDevice = emulatedDevice(&ua)
+	}
+
+	if os := hintPlatforms[strings.Trim(hints.Platform, `"`)]; ua.OS == "" && os != "" {
+		ua.OS = os
+		ua.OSInferred = true
+	}
+	return ua
+}
+
+// hintBrowser returns browser name of the first known brand in Sec-CH-UA list.
+func hintBrowser(list string) string {
+	name := ""
+	eachBrand(list, func(brand, _ string) bool {
+		name = hintBrands[brand]
+		return name == ""
+	})
+	return name
+}
+
+// hintVersion returns version of the browser from Sec-CH-UA-Full-Version-List.
+// Unknown brands such as GREASE "Not_A Brand" never match.
+func hintVersion(list, browser string) string {
+	if browser == "" {
+		return ""
+	}
+	ver := ""
+	eachBrand(list, func(brand, v string) bool {
+		if b, ok := hintBrands[brand]; ok && b == browser {
+			ver = v
+			return false
+		}
+		return true
+	})
+	return ver
+}
+
+// eachBrand calls fn with brand and version of each "Brand";v="version" entry of the list
+// until fn returns false.
+func eachBrand(list string, fn func(brand, ver string) bool) {
+	for list != "" {
+		entry := list
+		if i := strings.IndexByte(list, ','); i != -1 {
+			entry, list = list[:i], list[i+1:]
+		} else {
+			list = ""
+		}
+		brand, ver := entry, ""
+		if i := strings.IndexByte(entry, ';'); i != -1 {
+			brand, ver = entry[:i], strings.TrimSpace(entry[i+1:])
+			ver = strings.Trim(strings.TrimPrefix(ver, "v="), `"`)
+		}
+		brand = strings.Trim(strings.TrimSpace(brand), `"`)
+		if !fn(brand, ver) {
+			return
+		}
+	}
+}
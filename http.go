@@ -0,0 +1,14 @@
+package useragent
+
+import "net/http"
+
+// FromRequest parses User-Agent header of the request using the default parser.
+// Zero UserAgent is returned if the header is empty.
+// It is safe to use concurrently.
+func FromRequest(r *http.Request) UserAgent {
+	userAgent := r.Header.Get("User-Agent")
+	if userAgent == "" {
+		return UserAgent{}
+	}
+	return defaultParser.Parse(userAgent)
+}
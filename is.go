@@ -30,9 +30,20 @@ func (ua UserAgent) IsChromeOS() bool {
 	return ua.OS == ChromeOS || ua.OS == "CrOS"
 }
 
-// IsOpera shorthand function to check if Name == Opera
+// IsWindowsPhone shorthand function to check if OS == Windows Phone
+func (ua UserAgent) IsWindowsPhone() bool {
+	return ua.OS == WindowsPhone
+}
+
+// IsBlackBerry shorthand function to check if OS == BlackBerry
+func (ua UserAgent) IsBlackBerry() bool {
+	return ua.OS == BlackBerry
+}
+
+// IsOpera shorthand function to check if browser is made by Opera,
+// i.e., Opera, Opera Mini, Opera Touch, Opera GX and other editions
 func (ua UserAgent) IsOpera() bool {
-	return ua.Name == Opera
+	return browserVendors[ua.Name] == "Opera"
 }
 
 // IsOperaMini shorthand function to check if Name == Opera Mini
@@ -80,6 +91,70 @@ func (ua UserAgent) IsFacebookbot() bool {
 	return ua.Name == FacebookExternalHit
 }
 
+// searchEngineCrawlers lists names of recognized search engine bots.
+// SEO tools and social network crawlers aren't included.
+var searchEngineCrawlers = map[string]bool{
+	Googlebot:   true,
+	Bingbot:     true,
+	YandexBot:   true,
+	Baiduspider: true,
+	SogouSpider: true,
+	YisouSpider: true,
+	DuckDuckBot: true,
+	Applebot:    true,
+	Yeti:        true,
+	Daum:        true,
+}
+
+// IsSearchEngineCrawler returns true if user agent is a known search engine crawler
+func (ua UserAgent) IsSearchEngineCrawler() bool {
+	return ua.Bot && searchEngineCrawlers[ua.Name]
+}
+
+// defaultMinVersions are minimum supported browser versions used by IsOutdated
+// unless the Parser is configured with WithMinVersions.
+// The thresholds need periodic updates as browsers release new versions.
+var defaultMinVersions = map[string]VersionNo{
+	Chrome:           {Major: 100},
+	Firefox:          {Major: 100},
+	Safari:           {Major: 14},
+	Edge:             {Major: 100},
+	Opera:            {Major: 86},
+	InternetExplorer: {Major: 12}, // Internet Explorer is retired
+}
+
+// IsOutdated returns true if browser version is lower than minimum supported version.
+// The built-in thresholds are used unless the Parser is configured with WithMinVersions.
+// Browsers without a known threshold or without a version are never outdated.
+func (ua UserAgent) IsOutdated() bool {
+	return ua.Outdated
+}
+
+// http2MinVersions are the first browser versions with HTTP/2 support over TLS.
+// Internet Explorer, the Android browser and Opera Mini are left out on purpose.
+var http2MinVersions = map[string]VersionNo{
+	Chrome:         {Major: 51},
+	Firefox:        {Major: 36},
+	Safari:         {Major: 9},
+	Edge:           {Major: 12},
+	Opera:          {Major: 38},
+	SamsungBrowser: {Major: 5},
+	YandexBrowser:  {Major: 16, Minor: 6},
+	Vivaldi:        {Major: 1},
+	Brave:          {Major: 1},
+}
+
+// SupportsHTTP2 returns true if the browser most likely supports HTTP/2 and modern TLS.
+// It's an approximation based on a conservative support matrix of browser versions,
+// so unknown browsers, bots and user agents without a version are reported as unsupported.
+func (ua UserAgent) SupportsHTTP2() bool {
+	min, ok := http2MinVersions[ua.Name]
+	if !ok || ua.VersionNo == (VersionNo{}) {
+		return false
+	}
+	return !ua.VersionNo.Less(min)
+}
+
 // IsUnknown returns true if the package can't determine the user agent reliably.
 // Fields like Name, OS, etc. might still have values.
 func (ua UserAgent) IsUnknown() bool {
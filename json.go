@@ -0,0 +1,129 @@
+package useragent
+
+import "encoding/json"
+
+// jsonUserAgent is the JSON representation of UserAgent.
+// Mobile, Tablet, Desktop and Bot are always present, other empty fields are omitted.
+type jsonUserAgent struct {
+	Name           string `json:"name,omitempty"`
+	Version        string `json:"version,omitempty"`
+	VersionNo      string `json:"version_no,omitempty"`
+	OS             string `json:"os,omitempty"`
+	OSVersion      string `json:"os_version,omitempty"`
+	OSVersionNo    string `json:"os_version_no,omitempty"`
+	Device         string `json:"device,omitempty"`
+	Mobile         bool   `json:"mobile"`
+	Tablet         bool   `json:"tablet"`
+	Desktop        bool   `json:"desktop"`
+	Bot            bool   `json:"bot"`
+	URL            string `json:"url,omitempty"`
+	String         string `json:"string,omitempty"`
+	Engine         string `json:"engine,omitempty"`
+	BotKind        string `json:"bot_kind,omitempty"`
+	OSInferred     bool   `json:"os_inferred,omitempty"`
+	Brand          string `json:"brand,omitempty"`
+	Channel        string `json:"channel,omitempty"`
+	ScreenClass    string `json:"screen_class,omitempty"`
+	EmulatedDevice string `json:"emulated_device,omitempty"`
+	Architecture   string `json:"architecture,omitempty"`
+	Language       string `json:"language,omitempty"`
+	Region         string `json:"region,omitempty"`
+	Watch          bool   `json:"watch,omitempty"`
+	TV             bool   `json:"tv,omitempty"`
+	Console        bool   `json:"console,omitempty"`
+	App            bool   `json:"app,omitempty"`
+	WebView        bool   `json:"webview,omitempty"`
+	Tool           bool   `json:"tool,omitempty"`
+	HealthCheck    bool   `json:"health_check,omitempty"`
+	Automation     bool   `json:"automation,omitempty"`
+	Browser64Bit   bool   `json:"browser_64bit,omitempty"`
+	Outdated       bool   `json:"outdated,omitempty"`
+	OSOutdated     bool   `json:"os_outdated,omitempty"`
+}
+
+// MarshalJSON encodes the user agent as an object with lowercase keys.
+// Empty strings and false flags other than mobile, tablet, desktop and bot are omitted,
+// VersionNo and OSVersionNo are encoded as dotted strings.
+func (ua UserAgent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonUserAgent{
+		Name:           ua.Name,
+		Version:        ua.Version,
+		VersionNo:      ua.VersionNoFull(),
+		OS:             ua.OS,
+		OSVersion:      ua.OSVersion,
+		OSVersionNo:    ua.OSVersionNoFull(),
+		Device:         ua.Device,
+		Mobile:         ua.Mobile,
+		Tablet:         ua.Tablet,
+		Desktop:        ua.Desktop,
+		Bot:            ua.Bot,
+		URL:            ua.URL,
+		String:         ua.String,
+		Engine:         ua.Engine,
+		BotKind:        ua.BotKind,
+		OSInferred:     ua.OSInferred,
+		Brand:          ua.Brand,
+		Channel:        ua.Channel,
+		ScreenClass:    ua.ScreenClass,
+		EmulatedDevice: ua.EmulatedDevice,
+		Architecture:   ua.Architecture,
+		Language:       ua.Language,
+		Region:         ua.Region,
+		Watch:          ua.Watch,
+		TV:             ua.TV,
+		Console:        ua.Console,
+		App:            ua.App,
+		WebView:        ua.WebView,
+		Tool:           ua.Tool,
+		HealthCheck:    ua.HealthCheck,
+		Automation:     ua.Automation,
+		Browser64Bit:   ua.Browser64Bit,
+		Outdated:       ua.Outdated,
+		OSOutdated:     ua.OSOutdated,
+	})
+}
+
+// UnmarshalJSON decodes the user agent encoded by MarshalJSON.
+func (ua *UserAgent) UnmarshalJSON(b []byte) error {
+	var v jsonUserAgent
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*ua = UserAgent{
+		URL:            v.URL,
+		String:         v.String,
+		Name:           v.Name,
+		Version:        v.Version,
+		Engine:         v.Engine,
+		BotKind:        v.BotKind,
+		OS:             v.OS,
+		OSVersion:      v.OSVersion,
+		OSInferred:     v.OSInferred,
+		Device:         v.Device,
+		Brand:          v.Brand,
+		Channel:        v.Channel,
+		ScreenClass:    v.ScreenClass,
+		EmulatedDevice: v.EmulatedDevice,
+		Architecture:   v.Architecture,
+		Language:       v.Language,
+		Region:         v.Region,
+		Mobile:         v.Mobile,
+		Tablet:         v.Tablet,
+		Desktop:        v.Desktop,
+		Watch:          v.Watch,
+		TV:             v.TV,
+		Console:        v.Console,
+		Bot:            v.Bot,
+		App:            v.App,
+		WebView:        v.WebView,
+		Tool:           v.Tool,
+		HealthCheck:    v.HealthCheck,
+		Automation:     v.Automation,
+		Browser64Bit:   v.Browser64Bit,
+		Outdated:       v.Outdated,
+		OSOutdated:     v.OSOutdated,
+	}
+	parseVersion(v.VersionNo, &ua.VersionNo)
+	parseVersion(v.OSVersionNo, &ua.OSVersionNo)
+	return nil
+}
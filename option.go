@@ -0,0 +1,105 @@
+package useragent
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithVersionComponents makes the parser rewrite Version to exactly n dot-separated components
+// derived from VersionNo, e.g., 59.0.3071.115 becomes 59.0.3071 and 10.1 becomes 10.1.0 when n is 3.
+// By default the version is left as found in user agent.
+func WithVersionComponents(n int) Option {
+	return func(p *Parser) {
+		p.versionComponents = n
+	}
+}
+
+// WithMinVersions sets minimum supported browser versions keyed by browser name
+// which are used to set Outdated instead of the built-in thresholds.
+func WithMinVersions(m map[string]VersionNo) Option {
+	return func(p *Parser) {
+		p.minVersions = m
+	}
+}
+
+// WithMinOSVersions sets minimum supported OS versions keyed by OS name
+// which are used to set OSOutdated instead of the built-in thresholds.
+func WithMinOSVersions(m map[string]VersionNo) Option {
+	return func(p *Parser) {
+		p.minOSVersions = m
+	}
+}
+
+// WithChromeOSAsDesktop controls whether ChromeOS devices are reported as desktop, which is the default.
+// When it is false, Desktop is left unset and UserAgent.DeviceClass returns chromeos.
+func WithChromeOSAsDesktop(desktop bool) Option {
+	return func(p *Parser) {
+		p.chromeOSAsDesktop = desktop
+	}
+}
+
+// WithStrictVersion makes the parser discard Version and VersionNo
+// unless the version consists of dot-separated numbers, e.g., 109.0b4 or dev are dropped.
+// Browser name is kept. By default any version found in user agent is reported.
+func WithStrictVersion(strict bool) Option {
+	return func(p *Parser) {
+		p.strictVersion = strict
+	}
+}
+
+// WithOverride makes the parser return result for the exact user agent string bypassing detection,
+// e.g., to pin a misdetected user agent until the parser is fixed.
+// String field of the result is set to the user agent.
+// The option can be passed multiple times to register several overrides.
+func WithOverride(userAgent string, result UserAgent) Option {
+	return func(p *Parser) {
+		if p.overrides == nil {
+			p.overrides = make(map[string]UserAgent)
+		}
+		p.overrides[userAgent] = result
+	}
+}
+
+// WithHeadlessAsBot controls whether headless Chrome is reported as a bot, which is the default.
+// Pass false when headless Chrome is known to be your own rendering service, e.g., print to PDF.
+func WithHeadlessAsBot(bot bool) Option {
+	return func(p *Parser) {
+		p.headlessAsBot = bot
+	}
+}
+
+// WithRequireMozillaPrefix makes the parser classify user agents claiming to be a browser
+// without Mozilla/ prefix as tools, e.g., Chrome/120.0.0.0 sent by a script.
+// Presto based Opera which starts with Opera/ is still a browser. It is off by default.
+func WithRequireMozillaPrefix(require bool) Option {
+	return func(p *Parser) {
+		p.requireMozillaPrefix = require
+	}
+}
+
+// WithEngineVersionFallback makes the parser report engine version, i.e., AppleWebKit version
+// or Gecko revision, as Version of a recognized browser which doesn't send its own version.
+// By default Version is left empty in that case.
+func WithEngineVersionFallback(fallback bool) Option {
+	return func(p *Parser) {
+		p.engineVersionFallback = fallback
+	}
+}
+
+// WithBotPatterns registers case-insensitive substrings which mark a user agent as a bot when found,
+// e.g., names of internal crawlers the parser doesn't know about.
+// The option can be passed multiple times to register more patterns.
+func WithBotPatterns(patterns []string) Option {
+	return func(p *Parser) {
+		for _, pattern := range patterns {
+			if pattern != "" {
+				p.botPatterns = append(p.botPatterns, pattern)
+			}
+		}
+	}
+}
+
+// WithBotPatterns returns a new parser configured as p which also marks a user agent as a bot
+// when one of case-insensitive substrings is found, see WithBotPatterns option.
+// p is left unchanged, so it stays safe to use concurrently.
+func (p *Parser) WithBotPatterns(substrings []string) *Parser {
+	return New(append(p.opts[:len(p.opts):len(p.opts)], WithBotPatterns(substrings))...)
+}
@@ -0,0 +1,76 @@
+package useragent
+
+// Operating system families returned by OSFamily
+const (
+	FamilyApple       = "Apple"
+	FamilyMicrosoft   = "Microsoft"
+	FamilyAndroidLike = "Android-like"
+	FamilyUnix        = "Unix"
+)
+
+// OSFamily returns family of the detected OS for coarse grouping:
+// iOS and macOS are Apple, Windows and Windows Phone are Microsoft,
+// Android, HarmonyOS and Fire OS are Android-like, Linux, FreeBSD, ChromeOS and Tizen are Unix.
+// Empty string is returned for other or unknown OS.
+func (ua UserAgent) OSFamily() string {
+	switch ua.OS {
+	case IOS, MacOS:
+		return FamilyApple
+	case Windows, WindowsPhone:
+		return FamilyMicrosoft
+	case Android, HarmonyOS, FireOS:
+		return FamilyAndroidLike
+	case Linux, FreeBSD, ChromeOS, Tizen:
+		return FamilyUnix
+	}
+	return ""
+}
+
+// IsOSAtLeast returns true if OS matches os and its version is at least major.minor,
+// e.g., ua.IsOSAtLeast(useragent.IOS, 15, 0).
+func (ua UserAgent) IsOSAtLeast(os string, major, minor int) bool {
+	if ua.OS != os {
+		return false
+	}
+	return !ua.OSVersionNo.Less(VersionNo{Major: major, Minor: minor})
+}
+
+// defaultMinOSVersions are minimum supported OS versions used by IsOSOutdated
+// unless the Parser is configured with WithMinOSVersions.
+// The thresholds need periodic updates as vendors end support of OS releases.
+// Windows is compared by NT version and macOS can't be checked beyond 10.15,
+// since newer releases keep reporting Mac OS X 10_15_7.
+var defaultMinOSVersions = map[string]VersionNo{
+	Android: {Major: 8},
+	IOS:     {Major: 14},
+	Windows: {Major: 10},
+	MacOS:   {Major: 10, Minor: 15},
+}
+
+// IsOSOutdated returns true if OS version is lower than minimum supported version, i.e., the OS is end-of-life.
+// The built-in thresholds are used unless the Parser is configured with WithMinOSVersions.
+// OS without a known threshold or without a version is never outdated.
+func (ua UserAgent) IsOSOutdated() bool {
+	return ua.OSOutdated
+}
+
+// windowsNames maps Windows NT versions to marketing names.
+var windowsNames = map[string]string{
+	"10.0": "Windows 10",
+	"6.3":  "Windows 8.1",
+	"6.2":  "Windows 8",
+	"6.1":  "Windows 7",
+	"6.0":  "Windows Vista",
+	"5.1":  "Windows XP",
+}
+
+// WindowsName returns marketing name of Windows, e.g., Windows 7 for Windows NT 6.1.
+// Windows 11 still reports Windows NT 10.0 and can't be told apart from Windows 10 by user agent,
+// so Windows 10 is returned for both.
+// Empty string is returned for other OS or unknown NT version.
+func (ua UserAgent) WindowsName() string {
+	if ua.OS != Windows {
+		return ""
+	}
+	return windowsNames[ua.OSVersion]
+}
@@ -2,26 +2,46 @@ package useragent
 
 import (
 	"bytes"
-	"regexp"
 	"strings"
 	"sync"
 )
 
 // UserAgent struct containing all data extracted from parsed user-agent string
 type UserAgent struct {
-	VersionNo   VersionNo
-	OSVersionNo VersionNo
-	URL         string
-	String      string
-	Name        string
-	Version     string
-	OS          string
-	OSVersion   string
-	Device      string
-	Mobile      bool
-	Tablet      bool
-	Desktop     bool
-	Bot         bool
+	VersionNo      VersionNo
+	OSVersionNo    VersionNo
+	URL            string
+	String         string
+	Name           string
+	Version        string
+	Engine         string
+	BotKind        string
+	OS             string
+	OSVersion      string
+	OSInferred     bool
+	Device         string
+	Brand          string
+	Channel        string
+	ScreenClass    string
+	EmulatedDevice string
+	Architecture   string
+	Language       string
+	Region         string
+	Mobile         bool
+	Tablet         bool
+	Desktop        bool
+	Watch          bool
+	TV             bool
+	Console        bool
+	Bot            bool
+	App            bool
+	WebView        bool
+	Tool           bool
+	HealthCheck    bool
+	Automation     bool
+	Browser64Bit   bool
+	Outdated       bool
+	OSOutdated     bool
 }
 
 // Constants for browsers and operating systems for easier comparison
@@ -35,10 +55,19 @@ const (
 	FreeBSD      = "FreeBSD"
 	ChromeOS     = "ChromeOS"
 	BlackBerry   = "BlackBerry"
+	Tizen        = "Tizen"
+	HarmonyOS    = "HarmonyOS"
+	Fuchsia      = "Fuchsia"
+	FireOS       = "Fire OS"
+	WebOS        = "webOS"
+	KaiOS        = "KaiOS"
 
 	Opera            = "Opera"
 	OperaMini        = "Opera Mini"
 	OperaTouch       = "Opera Touch"
+	OperaGX          = "Opera GX"
+	OperaNeon        = "Opera Neon"
+	OperaCrypto      = "Opera Crypto"
 	Chrome           = "Chrome"
 	HeadlessChrome   = "Headless Chrome"
 	Firefox          = "Firefox"
@@ -46,29 +75,146 @@ const (
 	Safari           = "Safari"
 	Edge             = "Edge"
 	Vivaldi          = "Vivaldi"
-
-	GoogleAdsBot        = "Google Ads Bot"
-	Googlebot           = "Googlebot"
-	Twitterbot          = "Twitterbot"
-	FacebookExternalHit = "facebookexternalhit"
-	Applebot            = "Applebot"
-	Bingbot             = "Bingbot"
+	Netscape         = "Netscape"
+	SogouBrowser     = "Sogou Mobile Browser"
+	Brave            = "Brave"
+	YandexBrowser    = "Yandex Browser"
+	Whale            = "Whale"
+	UCBrowser        = "UC Browser"
+	SamsungBrowser   = "Samsung Browser"
+	AmazonSilk       = "Amazon Silk"
+
+	GoogleAdsBot                    = "Google Ads Bot"
+	Googlebot                       = "Googlebot"
+	GoogleInspectionTool            = "Google Inspection Tool"
+	GoogleStructuredDataTestingTool = "Google Structured Data Testing Tool"
+	GoogleAppsScript                = "Google Apps Script"
+	GoogleDocs                      = "Google Docs"
+	GoogleReadAloud                 = "Google-Read-Aloud"
+	Twitterbot                      = "Twitterbot"
+	FacebookExternalHit             = "facebookexternalhit"
+	FacebookCatalog                 = "facebookcatalog"
+	Applebot                        = "Applebot"
+	ApplebotExtended                = "Applebot-Extended"
+	TelegramBot                     = "TelegramBot"
+	Slackbot                        = "Slackbot"
+	Discordbot                      = "Discordbot"
+	WhatsApp                        = "WhatsApp"
+	LinkedInBot                     = "LinkedInBot"
+	Pinterest                       = "Pinterest"
+	UptimeRobot                     = "UptimeRobot"
+	Pingdom                         = "Pingdom"
+	Bluesky                         = "Bluesky"
+	WordPress                       = "WordPress"
+	SkypeURIPreview                 = "SkypeUriPreview"
+	MicrosoftPreview                = "MicrosoftPreview"
+	Bingbot                         = "Bingbot"
+	YandexBot                       = "YandexBot"
+	Baiduspider                     = "Baiduspider"
+	SogouSpider                     = "Sogou web spider"
+	YisouSpider                     = "YisouSpider"
+	Bytespider                      = "Bytespider"
+	DuckDuckBot                     = "DuckDuckBot"
+	Yeti                            = "Yeti"
+	Daum                            = "Daum"
+
+	GPTBot         = "GPTBot"
+	ChatGPTUser    = "ChatGPT-User"
+	OAISearchBot   = "OAI-SearchBot"
+	ClaudeBot      = "ClaudeBot"
+	AnthropicAI    = "anthropic-ai"
+	PerplexityBot  = "PerplexityBot"
+	CCBot          = "CCBot"
+	GoogleExtended = "Google-Extended"
+
+	ArchAMD64 = "amd64"
+	ArchARM64 = "arm64"
+	ArchARM   = "arm"
+	ArchX86   = "x86"
+
+	EngineBlink    = "Blink"
+	EngineWebKit   = "WebKit"
+	EngineGecko    = "Gecko"
+	EngineTrident  = "Trident"
+	EngineEdgeHTML = "EdgeHTML"
+	EnginePresto   = "Presto"
+
+	BotCrawler = "crawler"
+	BotPreview = "preview"
+	BotMonitor = "monitor"
+	BotAI      = "ai"
+
+	ScreenSmall  = "small"
+	ScreenMedium = "medium"
+	ScreenLarge  = "large"
+
+	ChannelStable  = "stable"
+	ChannelBeta    = "beta"
+	ChannelNightly = "nightly"
+	ChannelDev     = "dev"
+	ChannelCanary  = "canary"
 
 	FacebookApp  = "Facebook App"
 	InstagramApp = "Instagram App"
 	TiktokApp    = "TikTok App"
+	TelegramApp  = "Telegram App"
+	SogouApp     = "Sogou Search App"
+	WordPressApp = "WordPress App"
+	JetpackApp   = "Jetpack App"
+	SteamApp     = "Steam"
+	EpicGames    = "Epic Games Launcher"
+	GOGGalaxy    = "GOG Galaxy"
+	SmartHub     = "Samsung SmartHub"
+
+	Postman       = "Postman"
+	Insomnia      = "Insomnia"
+	ThunderClient = "Thunder Client"
+	Curl          = "curl"
+	Wget          = "Wget"
+	Wkhtmltopdf   = "wkhtmltopdf"
+	QtWebEngine   = "QtWebEngine"
+	Prince        = "Prince"
+
+	MicrosoftOffice = "Microsoft Office"
+	OpenAISDK       = "OpenAI SDK"
+	AnthropicSDK    = "Anthropic SDK"
 )
 
 // Parses parses user agents.
 // It is safe to use concurrently.
 type Parser struct {
-	buf    sync.Pool
-	tokens sync.Pool
+	buf     sync.Pool
+	tokens  sync.Pool
+	results sync.Pool
+
+	// versionComponents is a number of components Version is normalized to.
+	// Zero means the version is left as found in user agent.
+	versionComponents int
+	// minVersions are minimum supported browser versions keyed by browser name.
+	minVersions map[string]VersionNo
+	// minOSVersions are minimum supported OS versions keyed by OS name.
+	minOSVersions map[string]VersionNo
+	// chromeOSAsDesktop makes ChromeOS devices desktop, otherwise they are a class of their own.
+	chromeOSAsDesktop bool
+	// strictVersion discards versions which are not dot-separated numbers.
+	strictVersion bool
+	// requireMozillaPrefix classifies browsers without Mozilla prefix as tools.
+	requireMozillaPrefix bool
+	// headlessAsBot marks headless Chrome as a bot.
+	headlessAsBot bool
+	// botPatterns are case-insensitive substrings marking user agent as a bot.
+	botPatterns []string
+	// engineVersionFallback reports engine version for browsers without own version.
+	engineVersionFallback bool
+	// overrides are predetermined results keyed by exact user agent string.
+	overrides map[string]UserAgent
+	// opts are options the parser was created with, builders derive new parsers from them.
+	opts []Option
 }
 
-// New creates a user agent parser.
-func New() *Parser {
-	return &Parser{
+// New creates a user agent parser configured with options.
+func New(opts ...Option) *Parser {
+	p := Parser{
 		buf: sync.Pool{New: func() interface{} {
 			return &bytes.Buffer{}
 		}},
@@ -77,7 +223,19 @@ func New() *Parser {
 				list: make([]property, 0, 8),
 			}
 		}},
+		results: sync.Pool{New: func() interface{} {
+			return &UserAgent{}
+		}},
+		minVersions:       defaultMinVersions,
+		minOSVersions:     defaultMinOSVersions,
+		chromeOSAsDesktop: true,
+		headlessAsBot:     true,
 	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	p.opts = opts
+	return &p
 }
 
 // defaultParser is the default Parser used by Parse.
@@ -89,24 +247,177 @@ func Parse(userAgent string) UserAgent {
 	return defaultParser.Parse(userAgent)
 }
 
+// ParseBytes parses a user agent given as bytes using the default parser.
+// It is safe to use concurrently.
+func ParseBytes(userAgent []byte) UserAgent {
+	return defaultParser.ParseBytes(userAgent)
+}
+
+// SameFamily returns true if both user agents are the same browser on the same OS ignoring versions,
+// e.g., the browser auto-updated within a session.
+// It is safe to use concurrently.
+func SameFamily(a, b string) bool {
+	uaA, uaB := defaultParser.Parse(a), defaultParser.Parse(b)
+	return uaA.Name != "" && uaA.Name == uaB.Name && uaA.OS == uaB.OS
+}
+
+// Tokenize splits a user agent into tokens using the default parser.
+// It is safe to use concurrently.
+func Tokenize(userAgent string) []Token {
+	return defaultParser.Tokenize(userAgent)
+}
+
+// IsBotUA reports whether a user agent is a bot using the default parser.
+// It is safe to use concurrently.
+func IsBotUA(userAgent string) bool {
+	return defaultParser.IsBotUA(userAgent)
+}
+
+// ParseOS parses only OS name and version of a user agent using the default parser.
+// It is safe to use concurrently.
+func ParseOS(userAgent string) (os, version string) {
+	return defaultParser.ParseOS(userAgent)
+}
+
+// Token is a product or comment token of a user agent, e.g. Chrome/120.0.0.0 or Windows NT 10.0.
+// Value holds version which follows slash or is split from a known OS token.
+type Token struct {
+	Key   string
+	Value string
+}
+
+// Tokenize splits a user agent into tokens the parser uses for detection
+// so that custom classification rules can be built on top of them.
+// Insignificant tokens such as KHTML, like Gecko are dropped.
+// It is safe to use concurrently.
+func (p *Parser) Tokenize(userAgent string) []Token {
+	tokens := p.tokens.Get().(*properties)
+	defer p.putTokens(tokens)
+
+	p.parse(userAgent, tokens)
+
+	tt := make([]Token, len(tokens.list))
+	for i, prop := range tokens.list {
+		tt[i] = Token{Key: prop.Key, Value: prop.Value}
+	}
+	return tt
+}
+
+// ParseOS parses only OS name and version of a user agent skipping browser and device detection.
+// Note, Parse might clear OS for some bots, e.g., Applebot.
+// It is safe to use concurrently.
+func (p *Parser) ParseOS(userAgent string) (os, version string) {
+	if override, ok := p.overrides[userAgent]; ok {
+		return override.OS, override.OSVersion
+	}
+
+	tokens := p.tokens.Get().(*properties)
+	defer p.putTokens(tokens)
+
+	p.parse(userAgent, tokens)
+
+	ua := UserAgent{String: userAgent}
+	detectOS(tokens, &ua)
+	return ua.OS, ua.OSVersion
+}
+
 // Parse parses a user agent.
 // It is safe to use concurrently.
 func (p *Parser) Parse(userAgent string) UserAgent {
-	ua := UserAgent{
-		String: userAgent,
+	var ua UserAgent
+	p.ParseInto(userAgent, &ua)
+	return ua
+}
+
+// ParseBytes parses a user agent read from a byte buffer, e.g., by an HTTP parser.
+// The bytes are converted to string once which is stored in String and reused by tokenizer.
+// It is safe to use concurrently.
+func (p *Parser) ParseBytes(userAgent []byte) UserAgent {
+	var ua UserAgent
+	p.ParseInto(string(userAgent), &ua)
+	return ua
+}
+
+// Acquire parses a user agent into a UserAgent taken from the pool.
+// It avoids allocating a UserAgent on every call in tight loops.
+// The caller owns the returned UserAgent until it is passed to Release,
+// after that it must not be used, though its string fields can be retained
+// since Go strings are immutable.
+// It is safe to use concurrently.
+func (p *Parser) Acquire(userAgent string) *UserAgent {
+	ua := p.results.Get().(*UserAgent)
+	p.ParseInto(userAgent, ua)
+	return ua
+}
+
+// Release returns the UserAgent obtained from Acquire back to the pool.
+func (p *Parser) Release(ua *UserAgent) {
+	*ua = UserAgent{}
+	p.results.Put(ua)
+}
+
+// ParseInto parses a user agent into ua resetting all its fields,
+// so the caller can reuse one UserAgent in hot loops instead of copying a new one on every call.
+// It is safe to use concurrently as long as ua is not shared.
+func (p *Parser) ParseInto(userAgent string, ua *UserAgent) {
+	if override, ok := p.overrides[userAgent]; ok {
+		*ua = override
+		ua.String = userAgent
+		return
 	}
 
+	*ua = UserAgent{String: userAgent}
+
 	tokens := p.tokens.Get().(*properties)
-	defer p.tokens.Put(tokens)
+	defer p.putTokens(tokens)
+
+	p.parse(userAgent, tokens)
+	p.detect(userAgent, tokens, ua)
+	p.refine(userAgent, tokens, ua)
+}
+
+// putTokens clears tokens and returns them to the pool.
+// Tokens are substrings of the user agent, so a leftover one would keep the whole string alive.
+func (p *Parser) putTokens(tokens *properties) {
+	for i := range tokens.list {
+		tokens.list[i] = property{}
+	}
 	tokens.list = tokens.list[:0]
+	p.tokens.Put(tokens)
+}
+
+// IsBotUA reports whether a user agent is a bot.
+// It skips form factor and version refinement of Parse,
+// so it is cheaper for filtering where only bot status matters.
+// It is safe to use concurrently.
+func (p *Parser) IsBotUA(userAgent string) bool {
+	if override, ok := p.overrides[userAgent]; ok {
+		return override.Bot
+	}
+
+	ua := UserAgent{String: userAgent}
+
+	tokens := p.tokens.Get().(*properties)
+	defer p.putTokens(tokens)
 
 	p.parse(userAgent, tokens)
+	p.detect(userAgent, tokens, &ua)
+	return ua.Bot
+}
 
-	// check is there URL
+// detect sets name, version, OS and device of a tokenized user agent,
+// and decides whether it is a bot or a tool.
+func (p *Parser) detect(userAgent string, tokens *properties, ua *UserAgent) {
+	// check is there URL,
+	// crawlers usually declare it right after their name and version
+	var urlOwner property
 	for i, token := range tokens.list {
 		if strings.HasPrefix(token.Key, "http://") || strings.HasPrefix(token.Key, "https://") {
 			ua.URL = token.Key
-			tokens.list = append(tokens.list[:i], tokens.list[i+1:]...)
+			if i > 0 {
+				urlOwner = tokens.list[i-1]
+			}
+			tokens.removeAt(i)
 			break
 		}
 	}
@@ -114,75 +425,63 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 	//fmt.Printf("%+v\n", tokens)
 
 	// OS lookup
-	switch {
-	case tokens.exists("Android"):
-		ua.OS = Android
-		var osIndex int
-		osIndex, ua.OSVersion = tokens.getIndexValue(Android)
-		ua.Tablet = strings.Contains(strings.ToLower(ua.String), "tablet")
-		ua.Device = tokens.findAndroidDevice(osIndex)
-
-	case tokens.exists("iPhone"):
-		ua.OS = IOS
-		ua.OSVersion = tokens.findMacOSVersion()
-		ua.Device = "iPhone"
-		ua.Mobile = true
-
-	case tokens.exists("iPad"):
-		ua.OS = IOS
-		ua.OSVersion = tokens.findMacOSVersion()
-		ua.Device = "iPad"
-		ua.Tablet = true
-
-	case tokens.exists("Windows NT"):
-		ua.OS = Windows
-		ua.OSVersion = tokens.get("Windows NT")
-		ua.Desktop = true
-
-	case tokens.exists("Windows Phone OS"):
-		ua.OS = WindowsPhone
-		ua.OSVersion = tokens.get("Windows Phone OS")
-		ua.Mobile = true
-
-	case tokens.exists("Macintosh"):
-		ua.OS = MacOS
-		ua.OSVersion = tokens.findMacOSVersion()
-		ua.Desktop = true
-
-	case tokens.exists("Linux"):
-		ua.OS = Linux
-		ua.OSVersion = tokens.get(Linux)
-		ua.Desktop = true
-
-	case tokens.exists("FreeBSD"):
-		ua.OS = FreeBSD
-		ua.OSVersion = tokens.get(FreeBSD)
-		ua.Desktop = true
+	detectOS(tokens, ua)
 
-	case tokens.exists("CrOS"):
-		ua.OS = ChromeOS
-		ua.OSVersion = tokens.get("CrOS")
-		ua.Desktop = true
-
-	case tokens.exists("BlackBerry"):
-		ua.OS = BlackBerry
-		ua.OSVersion = tokens.get("BlackBerry")
-		ua.Mobile = true
+	// legacy feature phones prefix device model with vendor name
+	if ua.OS == "" && ua.Device == "" {
+		if brand, device := tokens.findLegacyDevice(); device != "" {
+			ua.Brand = brand
+			ua.Device = device
+			ua.Mobile = true
+		}
 	}
 
+	aiCrawler := tokens.findAICrawler()
+	healthCheck := tokens.findHealthCheck()
 	switch {
+	// Rich Results Test, Mobile-Friendly Test and URL Inspection in Search Console
+	case tokens.exists("Google-InspectionTool"):
+		ua.Name = GoogleInspectionTool
+		ua.Version = tokens.get("Google-InspectionTool")
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	case tokens.startsWith("Google-Structured-Data-Testing-Tool"):
+		ua.Name = GoogleStructuredDataTestingTool
+		ua.Bot = true
+
 	case tokens.exists("Googlebot"):
 		ua.Name = Googlebot
 		ua.Version = tokens.get(Googlebot)
 		ua.Bot = true
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
+	// text-to-speech fetcher of Google Assistant and Chrome, formerly google-speakr
+	case tokens.existsAny("Google-Read-Aloud", "google-speakr"):
+		ua.Name = GoogleReadAloud
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
 	case tokens.existsAny("GoogleProber", "GoogleProducer"):
 		if name := tokens.findBestMatch(false); name != "" {
 			ua.Name = name
 		}
 		ua.Bot = true
 
+	// AI training, search and assistant crawlers
+	case aiCrawler != "":
+		ua.Name = aiCrawler
+		ua.Version = tokens.get(ua.Name)
+		ua.Bot = true
+
+	// Apple AI training crawler, it must be checked before Applebot search crawler
+	case tokens.exists("Applebot-Extended"):
+		ua.Name = ApplebotExtended
+		ua.Version = tokens.get(ApplebotExtended)
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+		ua.OS = ""
+
 	case tokens.exists("Applebot"):
 		ua.Name = Applebot
 		ua.Version = tokens.get(Applebot)
@@ -190,13 +489,280 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 		ua.OS = ""
 
+	// Naver search crawler
+	case tokens.exists("Yeti"):
+		ua.Name = Yeti
+		ua.Version = tokens.get(Yeti)
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	// Kakao search crawler
+	case tokens.existsAny("Daum", "Daumoa"):
+		ua.Name = Daum
+		ua.Version = tokens.get(Daum)
+		if ua.Version == "" {
+			ua.Version = tokens.get("Daumoa")
+		}
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	case tokens.exists("Baiduspider"):
+		ua.Name = Baiduspider
+		ua.Version = tokens.get(Baiduspider)
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	// Chinese crawlers, Yisou is Shenma search of Alibaba and Bytespider crawls for ByteDance
+	case tokens.exists("Sogou web spider"):
+		ua.Name = SogouSpider
+		ua.Version = tokens.get(SogouSpider)
+		ua.Bot = true
+
+	case tokens.exists("YisouSpider"):
+		ua.Name = YisouSpider
+		ua.Version = tokens.get(YisouSpider)
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	case tokens.exists("Bytespider"):
+		ua.Name = Bytespider
+		ua.Version = tokens.get(Bytespider)
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	case tokens.exists("DuckDuckBot"):
+		ua.Name = DuckDuckBot
+		ua.Version = tokens.get(DuckDuckBot)
+		ua.Bot = true
+
+	// Telegram link preview
+	case tokens.exists("TelegramBot"):
+		ua.Name = TelegramBot
+		ua.Version = tokens.get(TelegramBot)
+		ua.Bot = true
+
+	// Slack link unfurling, e.g. Slackbot-LinkExpanding 1.0 or Slackbot 1.0
+	case tokens.startsWith("Slackbot"):
+		ua.Name = Slackbot
+		for _, prop := range tokens.list {
+			if strings.HasPrefix(prop.Key, Slackbot) {
+				if i := strings.LastIndexByte(prop.Key, ' '); i != -1 {
+					ua.Version = prop.Key[i+1:]
+				}
+				break
+			}
+		}
+		ua.Bot = true
+
+	case tokens.exists("Discordbot"):
+		ua.Name = Discordbot
+		ua.Version = tokens.get(Discordbot)
+		ua.Bot = true
+
+	// WhatsApp link preview, e.g. WhatsApp/2.23.20.0 A
+	case tokens.exists("WhatsApp") && !strings.HasPrefix(userAgent, "Mozilla/"):
+		ua.Name = WhatsApp
+		ua.Version = tokens.get(WhatsApp)
+		ua.Bot = true
+
+	case tokens.exists("LinkedInBot"):
+		ua.Name = LinkedInBot
+		ua.Version = tokens.get(LinkedInBot)
+		ua.Bot = true
+
+	// Pinterest link preview, its crawler sends Pinterestbot token and in-app browser has Mozilla prefix
+	case tokens.exists("Pinterest") && !strings.HasPrefix(userAgent, "Mozilla/"):
+		ua.Name = Pinterest
+		ua.Version = tokens.get(Pinterest)
+		ua.Bot = true
+
+	// Teams and Skype link unfurling, e.g. SkypeUriPreview Preview/0.5
+	case tokens.exists("SkypeUriPreview Preview"):
+		ua.Name = SkypeURIPreview
+		ua.Version = tokens.get("SkypeUriPreview Preview")
+		ua.Bot = true
+
+	// Outlook and Office link unfurling
+	case tokens.existsAny("MicrosoftPreview", "Microsoft-Preview"):
+		ua.Name = MicrosoftPreview
+		ua.Version = tokens.get("MicrosoftPreview")
+		if ua.Version == "" {
+			ua.Version = tokens.get("Microsoft-Preview")
+		}
+		ua.Bot = true
+
+	// WordPress sites fetching pingbacks, oEmbeds and feeds, e.g. WordPress/6.4.2; https://example.com
+	case tokens.exists("WordPress") && !strings.HasPrefix(userAgent, "Mozilla/"):
+		ua.Name = WordPress
+		ua.Version = tokens.get(WordPress)
+		ua.Bot = true
+
+	// Bluesky and AT Protocol app views fetching link cards, e.g. Bluesky Cardyb/1.1
+	case tokens.startsWith("Bluesky"):
+		ua.Name = Bluesky
+		ua.Version = tokens.getByPrefix("Bluesky")
+		ua.Bot = true
+
+	// uptime monitors, e.g. Pingdom.com_bot_version_1.4_ or PingdomTMS/0.8.5 for transaction checks
+	case tokens.exists("UptimeRobot"):
+		ua.Name = UptimeRobot
+		ua.Version = tokens.get(UptimeRobot)
+		ua.Bot = true
+
+	case tokens.startsWith("Pingdom"):
+		ua.Name = Pingdom
+		ua.Version = tokens.get("PingdomTMS")
+		if v := strings.TrimPrefix(userAgent, "Pingdom.com_bot_version_"); v != userAgent {
+			if i := strings.IndexByte(v, '_'); i != -1 {
+				v = v[:i]
+			}
+			ua.Version = v
+		}
+		ua.Bot = true
+
+	// load balancer, CDN and orchestrator probes
+	case healthCheck != "":
+		ua.Name = healthCheck
+		ua.Version = tokens.get(ua.Name)
+		ua.Tool = true
+		ua.HealthCheck = true
+
+	// UrlFetchApp in Apps Script and IMPORTHTML, IMPORTDATA functions in Sheets
+	case tokens.exists("Google-Apps-Script"):
+		ua.Name = GoogleAppsScript
+		ua.Bot = true
+		ua.Tool = true
+
+	case tokens.exists("GoogleDocs"):
+		ua.Name = GoogleDocs
+		ua.Bot = true
+		ua.Tool = true
+
+	// Word, Excel, PowerPoint and OneNote fetching linked or embedded content
+	case tokens.startsWith("Microsoft Office") || tokens.exists("ms-office"):
+		ua.Name = MicrosoftOffice
+		ua.Version = tokens.findOfficeVersion()
+		ua.Tool = true
+
+	// API client libraries send language after slash and version as a separate token, e.g. OpenAI/Python 1.3.0
+	case tokens.get("OpenAI") != "":
+		ua.Name = OpenAISDK
+		ua.Version = tokens.findSDKVersion("OpenAI")
+		ua.Tool = true
+
+	case tokens.get("Anthropic") != "":
+		ua.Name = AnthropicSDK
+		ua.Version = tokens.findSDKVersion("Anthropic")
+		ua.Tool = true
+
+	// command line clients, curl may list its libraries after own token, e.g. libcurl/8.1.0 OpenSSL/3.0 zlib/1.2
+	case tokens.exists("curl"):
+		ua.Name = Curl
+		ua.Version = tokens.get("curl")
+		ua.Tool = true
+
+	case tokens.exists("Wget"):
+		ua.Name = Wget
+		ua.Version = tokens.get("Wget")
+		ua.Tool = true
+
+	// server-side HTML to PDF renderers
+	case tokens.startsWith("wkhtmltopdf"):
+		ua.Name = Wkhtmltopdf
+		ua.Version = tokens.get("wkhtmltopdf")
+		ua.Tool = true
+
+	// Qt based browsers send QtWebEngine token as well
+	case tokens.exists("Falkon"):
+		ua.Name = "Falkon"
+		ua.Version = tokens.get("Falkon")
+
+	case tokens.exists("qutebrowser"):
+		ua.Name = "qutebrowser"
+		ua.Version = tokens.get("qutebrowser")
+
+	case tokens.exists("QtWebEngine"):
+		ua.Name = QtWebEngine
+		ua.Version = tokens.get("QtWebEngine")
+		ua.Tool = true
+
+	case tokens.existsAny("Prince", "PrinceXML"):
+		ua.Name = Prince
+		ua.Version = tokens.get("Prince")
+		if ua.Version == "" {
+			ua.Version = tokens.get("PrinceXML")
+		}
+		ua.Tool = true
+
+	// Roku apps append their name after platform token, e.g. Roku/DVP-12.5 (12.5.0.4174-88) Netflix/5.2
+	case tokens.exists("Roku"):
+		ua.Name = "Roku"
+		ua.Version = tokens.get("Roku")
+		if i, _ := tokens.getIndexValue("Roku"); i < len(tokens.list)-1 {
+			if app := tokens.list[len(tokens.list)-1]; app.Value != "" {
+				ua.Name = app.Key
+				ua.Version = app.Value
+				ua.App = true
+			}
+		}
+
+	// Apple TV has no browser, its apps put their name first, e.g. Netflix/15.16.0 (AppleTV; tvOS 16.1; Scale/1.00)
+	case tokens.existsAny("AppleTV", "tvOS") && tokens.list[0].Value != "":
+		ua.Name = tokens.list[0].Key
+		ua.Version = tokens.list[0].Value
+		ua.App = true
+
+	// Android apps using system HTTP client send Dalvik VM token,
+	// e.g. Dalvik/2.1.0 (Linux; U; Android 9; SHIELD Android TV Build/PPR1.180610.011)
+	case tokens.exists("Dalvik"):
+		ua.Name = "Dalvik"
+		ua.Version = tokens.get("Dalvik")
+		ua.App = true
+
+	// API clients
+	case tokens.exists("PostmanRuntime"):
+		ua.Name = Postman
+		ua.Version = tokens.get("PostmanRuntime")
+		ua.Tool = true
+
+	case tokens.exists("insomnia"):
+		ua.Name = Insomnia
+		ua.Version = tokens.get("insomnia")
+		ua.Tool = true
+
+	case tokens.exists("Thunder Client"):
+		ua.Name = ThunderClient
+		ua.Tool = true
+
+	// Opera Mini must be checked before Opera, since it is also sent with Opera/9.80 token
 	case tokens.get("Opera Mini") != "":
 		ua.Name = OperaMini
 		ua.Version = tokens.get(OperaMini)
 		ua.Mobile = true
 
-	case tokens.get("OPR") != "":
+	// Presto based Opera froze its token at Opera/9.80 and reports real version in Version token
+	case tokens.get("Opera") == "9.80" && tokens.exists("Presto"):
 		ua.Name = Opera
+		ua.Version = tokens.get("Version")
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+		// set-top boxes embedding Opera Devices SDK, OMI is Opera Media Infrastructure
+		ua.TV = tokens.existsAny("Opera TV Store", "OMI")
+
+	// Opera variants must be checked before generic OPR
+	case tokens.get("OPX") != "" || tokens.get("OPRGX") != "":
+		ua.Name = OperaGX
+		ua.Version = tokens.get("OPX")
+		if ua.Version == "" {
+			ua.Version = tokens.get("OPRGX")
+		}
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	// experimental Opera products append their edition after the OPR token
+	case tokens.get("OPR") != "" && tokens.existsAny("Edition Neon", "Edition Crypto"):
+		ua.Name = OperaNeon
+		if tokens.exists("Edition Crypto") {
+			ua.Name = OperaCrypto
+		}
 		ua.Version = tokens.get("OPR")
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
@@ -205,6 +771,11 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		ua.Version = tokens.get("OPT")
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
+	case tokens.get("OPR") != "":
+		ua.Name = Opera
+		ua.Version = tokens.get("OPR")
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
 	// Opera on iOS
 	case tokens.get("OPiOS") != "":
 		ua.Name = Opera
@@ -223,11 +794,14 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		ua.Version = tokens.get("FxiOS")
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
-	case tokens.get("Firefox") != "":
+	// custom builds might send Firefox token without version
+	case tokens.exists(Firefox):
 		ua.Name = Firefox
 		ua.Version = tokens.get(Firefox)
-		ua.Mobile = tokens.exists("Mobile")
-		ua.Tablet = tokens.exists("Tablet")
+		ua.Channel = firefoxChannel(ua.Version)
+		// some Android builds send neither Mobile nor Tablet token
+		ua.Tablet = ua.Tablet || tokens.exists("Tablet")
+		ua.Mobile = !ua.Tablet && (ua.OS == Android || tokens.exists("Mobile"))
 
 	case tokens.get("Vivaldi") != "":
 		ua.Name = Vivaldi
@@ -260,23 +834,56 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 	case tokens.get("bingbot") != "":
 		ua.Name = Bingbot
 		ua.Version = tokens.get("bingbot")
+		ua.Bot = true
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
 	case tokens.get("YandexBot") != "":
-		ua.Name = "YandexBot"
-		ua.Version = tokens.get("YandexBot")
+		ua.Name = YandexBot
+		ua.Version = tokens.get(YandexBot)
+		ua.Bot = true
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
-	case tokens.get("SamsungBrowser") != "":
-		ua.Name = "Samsung Browser"
-		ua.Version = tokens.get("SamsungBrowser")
+	case tokens.get("YaBrowser") != "":
+		ua.Name = YandexBrowser
+		ua.Version = tokens.get("YaBrowser")
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
+	// Whale takes precedence over Samsung Browser when both tokens are sent,
+	// Samsung Internet never adds Whale token, so its presence identifies Naver Whale
+	case tokens.get("Whale") != "":
+		ua.Name = Whale
+		ua.Version = tokens.get(Whale)
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
+	case tokens.get("Silk") != "":
+		ua.Name = AmazonSilk
+		ua.Version = tokens.get("Silk")
+
+	// UC Browser sends UBrowser token on desktop
+	case tokens.get("UCBrowser") != "" || tokens.get("UBrowser") != "":
+		ua.Name = UCBrowser
+		ua.Version = tokens.get("UCBrowser")
+		if ua.Version == "" {
+			ua.Version = tokens.get("UBrowser")
+		}
+		ua.Mobile = !ua.Desktop
+
+	// SamsungBrowser token without a version is merged with the following one, e.g. SamsungBrowser Version/9.2
+	case tokens.startsWith("SamsungBrowser"):
+		ua.Name = SamsungBrowser
+		ua.Version = tokens.getByPrefix("SamsungBrowser")
+		if ua.Version == "" {
+			ua.Version = tokens.get("Version")
+		}
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+		// Galaxy Tab models
+		ua.Tablet = ua.Tablet || strings.Contains(ua.Device, "SM-T")
+
 	case tokens.get("HeadlessChrome") != "":
 		ua.Name = HeadlessChrome
 		ua.Version = tokens.get("HeadlessChrome")
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
-		ua.Bot = true
+		ua.Bot = p.headlessAsBot
 
 	case tokens.existsAny("AdsBot-Google-Mobile", "Mediapartners-Google", "AdsBot-Google"):
 		ua.Name = GoogleAdsBot
@@ -299,18 +906,81 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 	case tokens.exists("FBAN"):
 		ua.Name = FacebookApp
 		ua.Version = tokens.get("FBAN")
+		ua.App = true
 
 	case tokens.exists("FB_IAB"):
 		ua.Name = FacebookApp
 		ua.Version = tokens.get("FBAV")
+		ua.App = true
 
 	case tokens.startsWith("Instagram"):
 		ua.Name = InstagramApp
 		ua.Version = tokens.findInstagramVersion()
+		ua.App = true
+
+	// WordPress and Jetpack mobile apps webview, e.g. wp-iphone/23.6 or jetpack-android/23.8
+	case tokens.startsWith("wp-"):
+		ua.Name = WordPressApp
+		ua.Version = tokens.getByPrefix("wp-")
+		ua.Mobile = true
+		ua.App = true
+
+	case tokens.startsWith("jetpack-"):
+		ua.Name = JetpackApp
+		ua.Version = tokens.getByPrefix("jetpack-")
+		ua.Mobile = true
+		ua.App = true
 
 	case tokens.exists("BytedanceWebview"):
 		ua.Name = TiktokApp
 		ua.Version = tokens.get("app_version")
+		ua.App = true
+
+	// Telegram in-app browser on Android
+	case tokens.exists("Telegram-Android"):
+		ua.Name = TelegramApp
+		ua.Version = tokens.get("Telegram-Android")
+		ua.Mobile = true
+		ua.App = true
+
+	// game launchers embedding Chromium, Steam sends build channel instead of version,
+	// e.g. Valve Steam Client/default/1596241936
+	case tokens.startsWith("Valve Steam"):
+		ua.Name = SteamApp
+		ua.App = true
+
+	// Epic version has build suffix, e.g. EpicGamesLauncher/15.17.1-27123890+++Portal+Release-Live
+	case tokens.exists("EpicGamesLauncher"):
+		ua.Name = EpicGames
+		ua.Version = tokens.get("EpicGamesLauncher")
+		if i := strings.IndexByte(ua.Version, '-'); i != -1 {
+			ua.Version = ua.Version[:i]
+		}
+		ua.App = true
+
+	case tokens.startsWith("GOGGalaxy"):
+		ua.Name = GOGGalaxy
+		ua.Version = tokens.getByPrefix("GOGGalaxy")
+		ua.App = true
+
+	// apps and app store of Samsung smart TVs before Tizen, e.g. SmartHub; SMART-TV; U; Linux/SmartTV; Maple2012
+	case tokens.exists("SmartHub"):
+		ua.Name = SmartHub
+		ua.Brand = "Samsung"
+		ua.TV = true
+		ua.App = true
+
+	case tokens.get("SogouMobileBrowser") != "":
+		ua.Name = SogouBrowser
+		ua.Version = tokens.get("SogouMobileBrowser")
+		ua.Mobile = true
+
+	// Sogou Search app webview
+	case tokens.startsWith("SogouSearch"):
+		ua.Name = SogouApp
+		ua.Version = tokens.getByPrefix("SogouSearch")
+		ua.Mobile = true
+		ua.App = true
 
 	case tokens.get("HuaweiBrowser") != "":
 		ua.Name = "Huawei Browser"
@@ -326,6 +996,13 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		ua.Version = tokens.get("NetFront")
 		ua.Mobile = true
 
+	// generic crawler pattern Mozilla/5.0 (compatible; SomeBot/1.0; +http://example.com/bot)
+	case urlOwner.Value != "" && strings.Contains(userAgent, "compatible"):
+		ua.Name = urlOwner.Key
+		ua.Version = urlOwner.Value
+		ua.Bot = true
+		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
+
 	// if chrome and Safari defined, find any other token sent descr
 	case tokens.exists(Chrome) && tokens.exists(Safari):
 		name := tokens.findBestMatch(true)
@@ -339,6 +1016,7 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 	case tokens.exists("Chrome"):
 		ua.Name = Chrome
 		ua.Version = tokens.get("Chrome")
+		ua.Channel = tokens.findChromeChannel()
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
 	case tokens.exists("Brave Chrome"):
@@ -356,8 +1034,16 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		}
 		ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
 
+	// Netscape Navigator 4 sent only Mozilla/4.x token which is ignored
+	case strings.HasPrefix(userAgent, "Mozilla/4.") && !strings.Contains(userAgent, "compatible") && tokens.findBestMatch(true) == "":
+		ua.Name = Netscape
+		ua.Version = strings.TrimPrefix(userAgent, "Mozilla/")
+		if i := strings.IndexAny(ua.Version, " ([;"); i != -1 {
+			ua.Version = ua.Version[:i]
+		}
+
 	default:
-		if ua.OS == "Android" && tokens.get("Version") != "" {
+		if (ua.OS == Android || ua.OS == FireOS) && tokens.get("Version") != "" {
 			ua.Name = "Android browser"
 			ua.Version = tokens.get("Version")
 			ua.Mobile = true
@@ -368,7 +1054,7 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 			} else {
 				ua.Name = ua.String
 			}
-			ua.Bot = strings.Contains(strings.ToLower(ua.Name), "bot")
+			ua.Bot = containsFold(ua.Name, "bot")
 			// If mobile flag has already been set, don't override it.
 			if !ua.Mobile {
 				ua.Mobile = tokens.existsAny("Mobile", "Mobile Safari")
@@ -376,7 +1062,41 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		}
 	}
 
-	if ua.IsAndroid() {
+	// real browsers start with Mozilla, except Presto based Opera
+	if p.requireMozillaPrefix && browserVendors[ua.Name] != "" &&
+		!strings.HasPrefix(userAgent, "Mozilla/") && !strings.HasPrefix(userAgent, "Opera/") {
+		ua.Tool = true
+	}
+
+	// if not already bot, check some popular bots and wether URL is set,
+	// tools might declare their homepage too
+	if !ua.Bot && !ua.Tool {
+		ua.Bot = ua.URL != ""
+	}
+
+	if !ua.Bot {
+		switch ua.Name {
+		// facebookcatalog crawls product catalogs, facebookexternalhit fetches link previews
+		case Twitterbot, FacebookExternalHit, FacebookCatalog:
+			ua.Bot = true
+		}
+	}
+
+	// crawlers registered with WithBotPatterns
+	if !ua.Bot {
+		for _, pattern := range p.botPatterns {
+			if containsFold(userAgent, pattern) {
+				ua.Bot = true
+				break
+			}
+		}
+	}
+}
+
+// refine sets form factor flags and properties which don't affect bot detection.
+func (p *Parser) refine(userAgent string, tokens *properties, ua *UserAgent) {
+	// browser cases might not see Mobile token on Palm phones
+	if ua.IsAndroid() || ua.OS == WebOS && !ua.TV {
 		ua.Mobile = true
 	}
 
@@ -385,95 +1105,288 @@ func (p *Parser) Parse(userAgent string) UserAgent {
 		ua.Mobile = false
 	}
 
-	// if not already bot, check some popular bots and wether URL is set
-	if !ua.Bot {
-		ua.Bot = ua.URL != ""
+	// Edge webviews embedded in Bing and Copilot apps
+	if ua.Name == Edge && tokens.existsAny("BingSapphire", "Copilot") {
+		ua.WebView = true
 	}
 
-	if !ua.Bot {
-		switch ua.Name {
-		case Twitterbot, FacebookExternalHit:
-			ua.Bot = true
-		}
+	// wearables are neither mobile nor tablet
+	if ua.Watch {
+		ua.Mobile = false
+		ua.Tablet = false
+	}
+
+	// browsers driven by WebDriver, e.g. Microsoft-WebDriver or Selenium-WebDriver token
+	ua.Automation = strings.Contains(ua.String, "WebDriver")
+
+	// game consoles, Xbox also sends Windows NT token
+	if console := tokens.findConsole(); console != "" {
+		ua.Console = true
+		ua.Device = console
+	}
+
+	// smart TVs and game consoles are form factors of their own
+	ua.TV = ua.TV || isTV(ua)
+	if ua.TV || ua.Console {
+		ua.Mobile = false
+		ua.Tablet = false
+		ua.Desktop = false
+	}
+
+	ua.Architecture = architecture(userAgent)
+	ua.Engine = engine(tokens, ua)
+	if ua.Bot {
+		ua.BotKind = botKinds[ua.Name]
+	}
+	ua.EmulatedDevice = emulatedDevice(ua)
+	ua.ScreenClass = screenClass(ua)
+	if ua.OS == ChromeOS && !p.chromeOSAsDesktop {
+		ua.Desktop = false
 	}
+	ua.Language, ua.Region = tokens.findLocale()
 
+	// custom builds might strip browser version, WebKit or Gecko version is better than nothing
+	if p.engineVersionFallback && ua.Version == "" && browserVendors[ua.Name] != "" {
+		ua.Version = tokens.findEngineVersion()
+	}
+
+	if p.strictVersion && !isNumericVersion(ua.Version) {
+		ua.Version = ""
+	}
 	parseVersion(ua.Version, &ua.VersionNo)
 	parseVersion(ua.OSVersion, &ua.OSVersionNo)
+	ua.Outdated = isBelowMin(p.minVersions, ua.Name, ua.VersionNo)
+	ua.OSOutdated = isBelowMin(p.minOSVersions, ua.OS, ua.OSVersionNo)
 
-	return ua
+	if p.versionComponents > 0 && hasNumericMajor(ua.Version) {
+		ua.Version = formatVersion(ua.VersionNo, p.versionComponents)
+	}
 }
 
+// detectOS sets OS, its version and device properties which can be derived from OS tokens.
+func detectOS(tokens *properties, ua *UserAgent) {
+	switch {
+	// KaiOS feature phones might send Android token as well
+	case tokens.exists("KAIOS"):
+		ua.OS = KaiOS
+		ua.OSVersion = tokens.get("KAIOS")
+		ua.Mobile = true
+
+	// HarmonyOS is sent along with Android token by older Huawei devices
+	case tokens.existsAny("HarmonyOS", "harmonyos", "OpenHarmony"):
+		ua.OS = HarmonyOS
+		osIndex, ver := tokens.getIndexValue("HarmonyOS")
+		if osIndex == -1 {
+			osIndex, ver = tokens.getIndexValue("harmonyos")
+		}
+		if osIndex == -1 {
+			osIndex, ver = tokens.getIndexValue("OpenHarmony")
+		}
+		ua.OSVersion = ver
+		ua.Tablet = containsFold(ua.String, "tablet")
+		ua.Device = tokens.findAndroidDevice(osIndex)
+		ua.Mobile = !ua.Tablet
+
+	case tokens.exists("Android"):
+		ua.OS = Android
+		var osIndex int
+		osIndex, ua.OSVersion = tokens.getIndexValue(Android)
+		ua.Tablet = containsFold(ua.String, "tablet")
+		ua.Device = tokens.findAndroidDevice(osIndex)
+		// Android System WebView reports its own version in Chrome token
+		ua.WebView = tokens.exists("wv")
+		// Fire OS is a fork of Android, Kindle Fire models start with KF,
+		// reported version is the Android version it is based on
+		if strings.HasPrefix(ua.Device, "KF") || tokens.startsWith("Silk") {
+			ua.OS = FireOS
+			ua.Tablet = ua.Tablet || strings.HasPrefix(ua.Device, "KF") || !tokens.existsAny("Mobile", "Mobile Safari")
+			ua.Mobile = !ua.Tablet
+		}
+
+	case tokens.exists("iPhone"):
+		ua.OS = IOS
+		ua.OSVersion = tokens.findMacOSVersion()
+		ua.Device = "iPhone"
+		ua.Mobile = true
+
+	case tokens.exists("iPad"):
+		ua.OS = IOS
+		ua.OSVersion = tokens.findMacOSVersion()
+		ua.Device = "iPad"
+		ua.Tablet = true
+
+	case tokens.exists("Windows NT"):
+		ua.OS = Windows
+		ua.OSVersion = tokens.get("Windows NT")
+		ua.Desktop = true
+		// WOW64 is a 32-bit browser on 64-bit Windows, its token is dropped by tokenizer
+		ua.Browser64Bit = tokens.existsAny("Win64", "x64") && !strings.Contains(ua.String, "WOW64")
+
+	case tokens.exists("Windows Phone OS"):
+		ua.OS = WindowsPhone
+		ua.OSVersion = tokens.get("Windows Phone OS")
+		ua.Mobile = true
+
+	// Since iPadOS 13 iPad sends desktop user agent, but it still has Mobile token
+	// or a token of browser available only on iOS, e.g. CriOS in Chrome.
+	// Reported macOS version is frozen and doesn't match iPadOS version.
+	case tokens.exists("Macintosh") && tokens.existsAny("Mobile", "CriOS", "FxiOS", "EdgiOS", "OPiOS"):
+		ua.OS = IOS
+		ua.OSInferred = true
+		ua.Device = "iPad"
+		ua.Tablet = true
+
+	case tokens.exists("Macintosh"):
+		ua.OS = MacOS
+		ua.OSVersion = tokens.findMacOSVersion()
+		ua.Desktop = true
+
+	// Tizen sends Linux token as well, Samsung TVs send it upper-cased
+	case tokens.exists("Tizen"):
+		ua.OS = Tizen
+		var osIndex int
+		osIndex, ua.OSVersion = tokens.getIndexValue(Tizen)
+		// Samsung smart TVs, SmartTV token might be taken as device name
+		ua.TV = tokens.existsAny("SMART-TV", "SmartTV")
+		ua.Device = tokens.findAndroidDevice(osIndex)
+		// Galaxy Watch models are SM-R
+		ua.Watch = strings.Contains(ua.Device, "SM-R")
+		ua.Mobile = !ua.Watch && !ua.TV
+
+	// LG smart TVs spell it with zero, legacy Palm phones send webOS
+	case tokens.existsAny("Web0S", "webOS"):
+		ua.OS = WebOS
+		ua.OSVersion = tokens.get("Web0S")
+		if ua.OSVersion == "" {
+			ua.OSVersion = tokens.get("webOS")
+		}
+		ua.TV = tokens.exists("Web0S") || strings.Contains(ua.String, "SmartTV")
+		ua.Mobile = !ua.TV
+
+	// Fuchsia ships on Nest Hub smart displays, so device type isn't assumed
+	case tokens.startsWith("Fuchsia"):
+		ua.OS = Fuchsia
+
+	case tokens.exists("Linux"):
+		ua.OS = Linux
+		// desktop Linux sends architecture instead of a kernel version, e.g. Linux x86_64 or Linux i686
+		if v := tokens.get(Linux); v != "" && v[0] >= '0' && v[0] <= '9' {
+			ua.OSVersion = v
+		}
+		ua.Desktop = true
+
+	case tokens.exists("FreeBSD"):
+		ua.OS = FreeBSD
+		ua.OSVersion = tokens.get(FreeBSD)
+		ua.Desktop = true
+
+	case tokens.exists("CrOS"):
+		ua.OS = ChromeOS
+		ua.OSVersion = tokens.get("CrOS")
+		ua.Desktop = true
+
+	case tokens.exists("BlackBerry"):
+		ua.OS = BlackBerry
+		ua.OSVersion = tokens.get("BlackBerry")
+		ua.Mobile = true
+	}
+}
+
+// parse splits user agent into tokens.
+// Tokens are substrings of the user agent whenever possible to avoid allocations,
+// only keys which had to be altered (e.g., colon replaced with space) are copied from the buffer.
 func (p *Parser) parse(userAgent string, tokens *properties) {
 	buff := p.buf.Get().(*bytes.Buffer)
 	defer p.buf.Put(buff)
 	buff.Reset()
 
-	val := p.buf.Get().(*bytes.Buffer)
-	defer p.buf.Put(val)
-	val.Reset()
+	// key is userAgent[keyStart:keyEnd] unless keyModified is set,
+	// value is always userAgent[valStart:valEnd]
+	var keyStart, keyEnd, valStart, valEnd int
+	keyModified := false
+
+	writeKey := func(i int) {
+		if buff.Len() == 0 {
+			keyStart = i
+		} else if keyEnd != i {
+			keyModified = true
+		}
+		keyEnd = i + 1
+		buff.WriteByte(userAgent[i])
+	}
+	key := func() string {
+		if buff.Len() == 0 {
+			return ""
+		}
+		if keyModified {
+			return buff.String()
+		}
+		return userAgent[keyStart:keyEnd]
+	}
+	resetKey := func() {
+		buff.Reset()
+		keyModified = false
+	}
 
 	slash := false
 	isURL := false
 
 	addToken := func() {
 		if buff.Len() != 0 {
-			s := strings.TrimSpace(buff.String())
-			if !ignore(s) {
+			s := strings.TrimSpace(key())
+			if s != "" && !ignore(s) {
 				if isURL {
 					s = strings.TrimPrefix(s, "+")
 				}
 
-				if val.Len() == 0 { // only if value don't exists
+				if valEnd == valStart { // only if value don't exists
 					var ver string
 					s, ver = checkVer(s) // determin version string and split
 					tokens.add(s, ver)
 				} else {
-					tokens.add(s, strings.TrimSpace(val.String()))
+					// AppleWebKit always starts a product token,
+					// so it was merged with the previous one when parenthesis wasn't closed
+					if i := strings.LastIndex(s, " "); i != -1 && s[i+1:] == "AppleWebKit" {
+						tokens.add(s[:i], "")
+						s = s[i+1:]
+					}
+					tokens.add(s, strings.TrimSpace(userAgent[valStart:valEnd]))
 				}
 			}
 		}
-		buff.Reset()
-		val.Reset()
+		resetKey()
+		valStart, valEnd = 0, 0
 		slash = false
 		isURL = false
 	}
 
-	parOpen := false
-	braOpen := false
-
-	bua := []byte(userAgent)
-	for i, c := range bua {
+	for i := 0; i < len(userAgent); i++ {
+		c := userAgent[i]
 
 		//fmt.Println(string(c), c)
 		switch {
 		case c == 41: // )
 			addToken()
-			parOpen = false
-
-		case (parOpen || braOpen) && c == 59: // ;
-			addToken()
 
 		case c == 59: // ;
 			addToken()
 
 		case c == 40: // (
 			addToken()
-			parOpen = true
 
 		case c == 91: // [
 			addToken()
-			braOpen = true
 		case c == 93: // ]
 			addToken()
-			braOpen = false
 
 		case c == 58: // :
 			if bytes.HasSuffix(buff.Bytes(), []byte("http")) || bytes.HasSuffix(buff.Bytes(), []byte("https")) {
 				// If we are part of a URL just write the character.
-				buff.WriteByte(c)
-			} else if i != len(bua)-1 && bua[i+1] != ' ' {
+				writeKey(i)
+			} else if i != len(userAgent)-1 && userAgent[i+1] != ' ' {
 				// If the following character is not a space, change to a space.
 				buff.WriteByte(' ')
+				keyModified = true
 			}
 			// Otherwise don't write as its probably a badly formatted key value separator.
 
@@ -481,22 +1394,25 @@ func (p *Parser) parse(userAgent string, tokens *properties) {
 			addToken()
 
 		case slash:
-			val.WriteByte(c)
+			if valEnd == valStart {
+				valStart = i
+			}
+			valEnd = i + 1
 
 		case c == 47 && !isURL: //   /
-			if i != len(bua)-1 && bua[i+1] == 47 && (bytes.HasSuffix(buff.Bytes(), []byte("http:")) || bytes.HasSuffix(buff.Bytes(), []byte("https:"))) {
-				buff.WriteByte(c)
+			if i != len(userAgent)-1 && userAgent[i+1] == 47 && (bytes.HasSuffix(buff.Bytes(), []byte("http:")) || bytes.HasSuffix(buff.Bytes(), []byte("https:"))) {
+				writeKey(i)
 				isURL = true
 			} else {
-				if ignore(buff.String()) {
-					buff.Reset()
+				if ignore(key()) {
+					resetKey()
 				} else {
 					slash = true
 				}
 			}
 
 		default:
-			buff.WriteByte(c)
+			writeKey(i)
 		}
 	}
 	addToken()
@@ -511,11 +1427,13 @@ func checkVer(s string) (name, v string) {
 	//v = s[i+1:]
 
 	switch s[:i] {
-	case "Linux", "Windows NT", "Windows Phone OS", "MSIE", "Android":
+	case "Linux", "Windows NT", "Windows Phone OS", "MSIE", "Android", "Tizen", "HarmonyOS", "OpenHarmony":
 		return s[:i], s[i+1:]
+	// ChromeOS sends architecture before platform version, e.g. CrOS x86_64 15359.58.0,
+	// architecture is reported separately
 	case "CrOS x86_64", "CrOS aarch64", "CrOS armv7l":
 		j := strings.LastIndex(s[:i], " ")
-		return s[:j], s[j+1 : i]
+		return s[:j], s[i+1:]
 	default:
 		return s, ""
 	}
@@ -529,10 +1447,142 @@ func checkVer(s string) (name, v string) {
 	// return s[:i], s[i+1:]
 }
 
+// deviceScreenClasses maps device model prefixes to screen classes
+// for devices whose screen doesn't match their mobile/tablet form factor.
+var deviceScreenClasses = []struct {
+	prefix string
+	class  string
+}{
+	{"SM-F9", ScreenMedium}, // Galaxy Z Fold
+	{"SM-X90", ScreenLarge}, // Galaxy Tab S Ultra
+	{"SM-X91", ScreenLarge},
+}
+
+// screenClass returns a rough screen size hint for the device.
+// Known device models take precedence, otherwise phones are small,
+// tablets are medium and desktops are large.
+func screenClass(ua *UserAgent) string {
+	for _, d := range deviceScreenClasses {
+		if strings.HasPrefix(ua.Device, d.prefix) {
+			return d.class
+		}
+	}
+	switch {
+	case ua.Watch:
+		return ScreenSmall
+	case ua.Tablet:
+		return ScreenMedium
+	case ua.Mobile:
+		return ScreenSmall
+	case ua.Desktop:
+		return ScreenLarge
+	}
+	return ""
+}
+
+// architectures maps user agent substrings to CPU architectures, 64-bit ones must be checked first.
+var architectures = []struct {
+	marker string
+	arch   string
+}{
+	{"x86_64", ArchAMD64},
+	{"Win64", ArchAMD64},
+	{"WOW64", ArchAMD64},
+	{"x64", ArchAMD64},
+	{"amd64", ArchAMD64},
+	{"aarch64", ArchARM64},
+	{"arm64", ArchARM64},
+	{"ARM64", ArchARM64},
+	{"arm_64", ArchARM64},
+	{"armv", ArchARM},
+	{"i686", ArchX86},
+	{"i586", ArchX86},
+	{"i386", ArchX86},
+}
+
+// engine returns rendering engine of a browser.
+// Every browser on iOS has to use WebKit, so Chrome, Firefox and Edge there are reported as WebKit.
+func engine(tokens *properties, ua *UserAgent) string {
+	switch {
+	case ua.OS == IOS && tokens.exists("AppleWebKit"):
+		return EngineWebKit
+	case tokens.exists("Presto"):
+		return EnginePresto
+	case tokens.existsAny("Trident", "MSIE"):
+		return EngineTrident
+	case tokens.get("Edge") != "":
+		return EngineEdgeHTML
+	case tokens.exists("AppleWebKit") && tokens.existsAny("Chrome", "HeadlessChrome", "Chromium"):
+		return EngineBlink
+	case tokens.exists("AppleWebKit"):
+		return EngineWebKit
+	case tokens.exists("Gecko"):
+		return EngineGecko
+	}
+	return ""
+}
+
+// architecture returns CPU architecture found in s.
+// WOW64 is a 32-bit browser on 64-bit Windows, so amd64 is reported, see Browser64Bit.
+// Apple Silicon Macs report Intel Mac OS X, their architecture is unknown.
+func architecture(s string) string {
+	for _, a := range architectures {
+		if strings.Contains(s, a.marker) {
+			return a.arch
+		}
+	}
+	return ""
+}
+
+// tvMarkers lists user agent substrings sent by smart TVs, TV sticks and set-top boxes.
+var tvMarkers = []string{"SmartTV", "SMART-TV", "GoogleTV", "Android TV", "BRAVIA", "CrKey", "Roku", "HbbTV", "AppleTV", "Apple TV"}
+
+// isTV returns true if user agent comes from a TV device.
+// Amazon Fire TV models start with AFT, e.g. AFTMM.
+func isTV(ua *UserAgent) bool {
+	if strings.HasPrefix(ua.Device, "AFT") {
+		return true
+	}
+	for _, m := range tvMarkers {
+		if strings.Contains(ua.String, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// firefoxChannel guesses Firefox release channel from its version string.
+// Nightly builds used to report a1 suffix (120.0a1) and Beta and Developer Edition
+// b suffix (109.0b4), but recent builds report plain major version on every channel.
+// ESR sends the same user agent as stable release of the same major version,
+// so it can't be inferred and is reported as stable.
+func firefoxChannel(ver string) string {
+	switch {
+	case ver == "":
+		return ""
+	case strings.Contains(ver, "a"):
+		return ChannelNightly
+	case strings.Contains(ver, "b"):
+		return ChannelBeta
+	}
+	return ChannelStable
+}
+
+// containsFold reports whether substr is within s ignoring ASCII case.
+// Unlike strings.ToLower it doesn't allocate.
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+	return false
+}
+
 // ignore retursn true if token should be ignored
 func ignore(s string) bool {
 	switch s {
-	case "KHTML, like Gecko", "U", "compatible", "Mozilla", "WOW64", "en", "en-us", "en-gb", "ru-ru", "Browser":
+	case "KHTML, like Gecko", "U", "compatible", "Mozilla", "WOW64", "en", "Browser":
 		return true
 	default:
 		return false
@@ -551,6 +1601,15 @@ func (p *properties) add(key, value string) {
 	p.list = append(p.list, property{Key: key, Value: value})
 }
 
+// removeAt removes property at index i keeping the order of the rest.
+// The freed slot is zeroed, so the pooled list doesn't keep its strings alive.
+func (p *properties) removeAt(i int) {
+	n := len(p.list) - 1
+	copy(p.list[i:], p.list[i+1:])
+	p.list[n] = property{}
+	p.list = p.list[:n]
+}
+
 func (p *properties) get(key string) string {
 	for _, prop := range p.list {
 		if prop.Key == key {
@@ -621,6 +1680,16 @@ func (p *properties) startsWith(value string) bool {
 	return false
 }
 
+// getByPrefix returns value of the first token starting with prefix.
+func (p *properties) getByPrefix(prefix string) string {
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, prefix) {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
 func (p *properties) findInstagramVersion() string {
 	for _, token := range p.list {
 		if strings.HasPrefix(token.Key, "Instagram") {
@@ -646,12 +1715,20 @@ func (p *properties) findBestMatch(withVerOnly bool) string {
 	for i := 0; i < n; i++ {
 		for _, prop := range p.list {
 			switch prop.Key {
-			case Chrome, Firefox, Safari, "Version", "Mobile", "Mobile Safari", "Mozilla", "AppleWebKit", "Windows NT", "Windows Phone OS", Android, "Macintosh", Linux, "GSA", "CrOS", "Tablet":
+			case Chrome, Firefox, Safari, "Version", "Mobile", "Mobile Safari", "Mozilla", "AppleWebKit", "Windows NT", "Windows Phone OS", Android, "Macintosh", Linux, "GSA", "CrOS", "Tablet", "Profile", "Configuration", "HarmonyOS", "OpenHarmony", Fuchsia, "Web0S", WebOS, "KAIOS":
 			default:
 				// don' pick if starts with number
 				if len(prop.Key) != 0 && prop.Key[0] >= 48 && prop.Key[0] <= 57 {
 					break
 				}
+				// nor a WebDriver hint appended to automated browser
+				if strings.HasSuffix(prop.Key, "WebDriver") {
+					break
+				}
+				// nor a locale tag such as en-us
+				if _, _, ok := parseLocale(prop.Key); ok && prop.Value == "" {
+					break
+				}
 				if i == 0 {
 					if prop.Value != "" { // in first check, only return keys with value
 						return prop.Key
@@ -665,35 +1742,271 @@ func (p *properties) findBestMatch(withVerOnly bool) string {
 	return ""
 }
 
-var rxMacOSVer = regexp.MustCompile(`[_\d\.]+`)
+// botKinds maps bot names to their purpose, other bots are left without a kind.
+var botKinds = map[string]string{
+	Googlebot:   BotCrawler,
+	Bingbot:     BotCrawler,
+	YandexBot:   BotCrawler,
+	Baiduspider: BotCrawler,
+	SogouSpider: BotCrawler,
+	YisouSpider: BotCrawler,
+	Bytespider:  BotCrawler,
+	DuckDuckBot: BotCrawler,
+	Applebot:    BotCrawler,
+	Yeti:        BotCrawler,
+	Daum:        BotCrawler,
+
+	Twitterbot:          BotPreview,
+	FacebookExternalHit: BotPreview,
+	TelegramBot:         BotPreview,
+	Slackbot:            BotPreview,
+	Discordbot:          BotPreview,
+	WhatsApp:            BotPreview,
+	LinkedInBot:         BotPreview,
+	Pinterest:           BotPreview,
+	SkypeURIPreview:     BotPreview,
+	MicrosoftPreview:    BotPreview,
+	Bluesky:             BotPreview,
+
+	UptimeRobot: BotMonitor,
+	Pingdom:     BotMonitor,
+
+	GPTBot:           BotAI,
+	ChatGPTUser:      BotAI,
+	OAISearchBot:     BotAI,
+	ClaudeBot:        BotAI,
+	AnthropicAI:      BotAI,
+	PerplexityBot:    BotAI,
+	CCBot:            BotAI,
+	GoogleExtended:   BotAI,
+	ApplebotExtended: BotAI,
+}
+
+// aiCrawlers lists tokens of AI crawlers, they are reported as names.
+// Google-Extended is a robots.txt product token, Google fetches content with its regular crawlers.
+var aiCrawlers = []string{GPTBot, ChatGPTUser, OAISearchBot, ClaudeBot, AnthropicAI, PerplexityBot, CCBot, GoogleExtended}
 
-func findVersion(s string) string {
-	if ver := rxMacOSVer.FindString(s); ver != "" {
-		return strings.Replace(ver, "_", ".", -1)
+// findAICrawler returns name of AI crawler if its token is found.
+func (p *properties) findAICrawler() string {
+	for _, prop := range p.list {
+		for _, name := range aiCrawlers {
+			if prop.Key == name {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// healthCheckers lists token prefixes of health checks sent by load balancers, CDNs and orchestrators.
+var healthCheckers = []string{"ELB-HealthChecker", "kube-probe", "GoogleHC", "Amazon CloudFront", "Akamai"}
+
+// findEngineVersion returns AppleWebKit version or Gecko revision from rv token, e.g. rv:109.0.
+func (p *properties) findEngineVersion() string {
+	if v := p.get("AppleWebKit"); v != "" {
+		return v
+	}
+	for _, prop := range p.list {
+		if strings.HasPrefix(prop.Key, "rv ") {
+			return prop.Key[len("rv "):]
+		}
+	}
+	return ""
+}
+
+// findHealthCheck returns the token of a known health check probe.
+func (p *properties) findHealthCheck() string {
+	for _, prop := range p.list {
+		for _, prefix := range healthCheckers {
+			if strings.HasPrefix(prop.Key, prefix) {
+				return prop.Key
+			}
+		}
+	}
+	return ""
+}
+
+// findOfficeVersion returns Microsoft Office version sent as Microsoft Office/16.0,
+// MSOffice 16 or a trailing release year such as Microsoft Office Word 2014.
+func (p *properties) findOfficeVersion() string {
+	for _, prop := range p.list {
+		switch {
+		case prop.Key == "Microsoft Office" && prop.Value != "":
+			return prop.Value
+		case strings.HasPrefix(prop.Key, "MSOffice "):
+			return prop.Key[len("MSOffice "):]
+		case strings.HasPrefix(prop.Key, "Microsoft Office "):
+			ver := prop.Key[strings.LastIndexByte(prop.Key, ' ')+1:]
+			if _, ok := atoi(ver); ok {
+				return ver
+			}
+		}
+	}
+	return ""
+}
+
+// findSDKVersion returns version token following the SDK token, e.g. 1.3.0 in OpenAI/Python 1.3.0.
+func (p *properties) findSDKVersion(key string) string {
+	i, _ := p.getIndexValue(key)
+	if i == -1 || i+1 >= len(p.list) {
+		return ""
+	}
+	if next := p.list[i+1]; next.Value == "" && next.Key[0] >= '0' && next.Key[0] <= '9' {
+		return next.Key
+	}
+	return ""
+}
+
+// findLocale returns language and region of a locale token such as en-us or pt_BR.
+// Region is empty when locale carries a script instead, e.g. zh-Hant.
+func (p *properties) findLocale() (lang, region string) {
+	for _, prop := range p.list {
+		if prop.Value != "" {
+			continue
+		}
+		if lang, region, ok := parseLocale(prop.Key); ok {
+			return strings.ToLower(lang), strings.ToUpper(region)
+		}
+	}
+	return "", ""
+}
+
+// parseLocale splits locale tag into language and region as they were sent.
+func parseLocale(s string) (lang, region string, ok bool) {
+	i := strings.IndexAny(s, "-_")
+	if i < 2 || i > 3 || !isLetters(s[:i]) {
+		return "", "", false
+	}
+	lang, rest := s[:i], s[i+1:]
+	// script subtag may be followed by region, e.g. zh-Hant-TW
+	if len(rest) > 4 && isLetters(rest[:4]) && (rest[4] == '-' || rest[4] == '_') {
+		rest = rest[5:]
+	}
+	switch {
+	case len(rest) == 2 && isLetters(rest):
+		return lang, rest, true
+	case len(rest) == 4 && isLetters(rest):
+		return lang, "", true
+	}
+	return "", "", false
+}
+
+// isLetters returns true if s consists of ASCII letters only.
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// findConsole returns game console name, e.g. PlayStation 5, Xbox Series X or Nintendo Switch.
+func (p *properties) findConsole() string {
+	console := ""
+	for _, prop := range p.list {
+		switch {
+		case strings.HasPrefix(prop.Key, "PlayStation "):
+			// PlayStation 4 sends its firmware version after a space, e.g. PlayStation 4 3.11
+			if i := strings.IndexByte(prop.Key[len("PlayStation "):], ' '); i != -1 {
+				return prop.Key[:len("PlayStation ")+i]
+			}
+			return prop.Key
+		case strings.HasPrefix(prop.Key, "Xbox "), strings.HasPrefix(prop.Key, "Nintendo "):
+			return prop.Key
+		case prop.Key == "Xbox":
+			// more specific model might follow, e.g. Xbox; Xbox One
+			console = prop.Key
+		}
+	}
+	return console
+}
+
+// findChromeChannel returns Chrome channel if UA is marked with channel token.
+// Chrome doesn't report its channel and version numbers of Canary, Dev, Beta and Stable
+// look alike, so empty string is returned unless a rare vendor token is present.
+func (p *properties) findChromeChannel() string {
+	switch {
+	case p.exists("Canary"):
+		return ChannelCanary
+	case p.exists("Dev"):
+		return ChannelDev
+	case p.exists("Beta"):
+		return ChannelBeta
 	}
 	return ""
 }
 
+// findVersion returns the first run of digits, dots and underscores in s
+// with underscores replaced by dots, e.g. 10_15_7 becomes 10.15.7.
+func findVersion(s string) string {
+	start := -1
+	end := len(s)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= '0' && c <= '9' || c == '.' || c == '_' {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			end = i
+			break
+		}
+	}
+	if start == -1 {
+		return ""
+	}
+	return strings.ReplaceAll(s[start:end], "_", ".")
+}
+
+// legacyVendors maps device model prefixes used by feature phones to brand names.
+var legacyVendors = []struct {
+	prefix string
+	brand  string
+}{
+	{"SonyEricsson", "Sony Ericsson"},
+	{"SAMSUNG-", "Samsung"},
+	{"LG-", "LG"},
+	{"MOTOROLA-", "Motorola"},
+	{"Motorola", "Motorola"},
+	{"MOT-", "Motorola"},
+}
+
+// findLegacyDevice finds vendor prefixed device token such as SonyEricssonK310iv or SAMSUNG-SGH-E250,
+// removes it from the list and returns brand and device model.
+func (p *properties) findLegacyDevice() (brand, device string) {
+	for i, prop := range p.list {
+		for _, v := range legacyVendors {
+			if strings.HasPrefix(prop.Key, v.prefix) && len(prop.Key) > len(v.prefix) {
+				p.removeAt(i)
+				return v.brand, strings.TrimLeft(prop.Key[len(v.prefix):], " -")
+			}
+		}
+	}
+	return "", ""
+}
+
 // findAndroidDevice in tokens
 func (p *properties) findAndroidDevice(startIndex int) string {
-	for i := startIndex; i < startIndex+1; i++ {
-		if len(p.list) > i+1 {
-			dev := p.list[i+1].Key
-			if len(dev) == 2 || (len(dev) == 5 && dev[2] == '-') {
-				// probably langage tag (en-us etc..), ignore and continue loop
-				continue
-			}
-			switch dev {
-			case Chrome, Firefox, Safari, "Opera Mini", "Presto", "Version", "Mobile", "Mobile Safari", "Mozilla", "AppleWebKit", "Windows NT", "Windows Phone OS", Android, "Macintosh", Linux, "CrOS":
-				// ignore this tokens, not device names
-			default:
-				if strings.Contains(strings.ToLower(dev), "tablet") {
-					p.list[i+1].Key = "Tablet" // leave Tablet tag for later table detection
-				} else {
-					p.list = append(p.list[:i+1], p.list[i+2:]...)
-				}
-				return strings.TrimSpace(strings.TrimSuffix(dev, "Build"))
+	for i := startIndex + 1; i < len(p.list) && i <= startIndex+2; i++ {
+		dev := p.list[i].Key
+		if len(dev) == 2 || (len(dev) == 5 && dev[2] == '-') {
+			// probably langage tag (en-us etc..), device name may follow it
+			continue
+		}
+		switch dev {
+		case Chrome, Firefox, Safari, "Opera Mini", "Presto", "Version", "Mobile", "Mobile Safari", "Mozilla", "AppleWebKit", "Windows NT", "Windows Phone OS", Android, "Macintosh", Linux, "CrOS":
+			// ignore this tokens, not device names
+			return ""
+		default:
+			if containsFold(dev, "tablet") {
+				p.list[i].Key = "Tablet" // leave Tablet tag for later table detection
+			} else {
+				p.removeAt(i)
 			}
+			return strings.TrimSpace(strings.TrimSuffix(dev, "Build"))
 		}
 	}
 	return ""
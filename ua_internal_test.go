@@ -0,0 +1,134 @@
+package useragent
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// ParseTokens returns tokens of the internal parse of the default parser,
+// so that the external tests can compare Tokenize with it over their tables.
+func ParseTokens(userAgent string) []Token {
+	tokens := &properties{}
+	defaultParser.parse(userAgent, tokens)
+	tt := make([]Token, 0, len(tokens.list))
+	for _, prop := range tokens.list {
+		tt = append(tt, Token{Key: prop.Key, Value: prop.Value})
+	}
+	return tt
+}
+
+func TestFindVersion(t *testing.T) {
+	tests := []struct {
+		s   string
+		ver string
+	}{
+		{"10_15_7", "10.15.7"},
+		{"15.4", "15.4"},
+		{"Intel Mac OS X 10_15_7", "10.15.7"},
+		{"CPU iPhone OS 17_1 like Mac OS X", "17.1"},
+		{"Instagram", ""},
+		{"Instagram 309.1.0.41.113", "309.1.0.41.113"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		got := findVersion(test.s)
+		if got != test.ver {
+			t.Errorf("findVersion(%q) should be %q not %q", test.s, test.ver, got)
+		}
+		if old := findVersionRegexp(test.s); got != old {
+			t.Errorf("findVersion(%q) is %q but regexp version returns %q", test.s, got, old)
+		}
+	}
+}
+
+func TestAtoi(t *testing.T) {
+	tests := []struct {
+		s  string
+		n  int
+		ok bool
+	}{
+		{"120", 120, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"0b4", 0, false},
+		{"99999999999999999999", 0, false},
+		{"123456789012345678901234567890", 0, false},
+	}
+	for _, test := range tests {
+		n, ok := atoi(test.s)
+		if n != test.n || ok != test.ok {
+			t.Errorf("atoi(%q) should be %d %v not %d %v", test.s, test.n, test.ok, n, ok)
+		}
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	p := New()
+	tests := []struct {
+		ua     string
+		device string
+		url    string
+	}{
+		{"Mozilla/5.0 (compatible; Yeti/1.1; +http://naver.me/spd)", "", "http://naver.me/spd"},
+		{"SonyEricssonK310iv/R4DA Browser/NetFront/3.3 Profile/MIDP-2.0 Configuration/CLDC-1.1", "K310iv", ""},
+		{"Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.125 Mobile Safari/537.36", "GT-I9300", ""},
+	}
+	for _, test := range tests {
+		tokens := &properties{}
+		p.parse(test.ua, tokens)
+		var ua UserAgent
+		ua.String = test.ua
+		p.detect(test.ua, tokens, &ua)
+
+		if ua.URL != test.url || ua.Device != test.device {
+			t.Errorf("%s\nURL and Device should be %q %q not %q %q", test.ua, test.url, test.device, ua.URL, ua.Device)
+		}
+		for _, token := range tokens.list {
+			if token.Key == test.url || token.Key == test.device {
+				t.Errorf("%s\ntoken %q should be removed", test.ua, token.Key)
+			}
+		}
+		// removed tokens must not stay referenced beyond the length of the list
+		for i, token := range tokens.list[len(tokens.list):cap(tokens.list)] {
+			if token != (property{}) {
+				t.Errorf("%s\nslot %d beyond length should be zeroed not %+v", test.ua, len(tokens.list)+i, token)
+			}
+		}
+
+		p.putTokens(tokens)
+		for i, token := range tokens.list[:cap(tokens.list)] {
+			if token != (property{}) {
+				t.Errorf("%s\nslot %d should be zeroed before returning to the pool not %+v", test.ua, i, token)
+			}
+		}
+	}
+}
+
+// rxMacOSVer is the regexp findVersion used to rely on, it's kept for comparison.
+var rxMacOSVer = regexp.MustCompile(`[_\d\.]+`)
+
+func findVersionRegexp(s string) string {
+	if ver := rxMacOSVer.FindString(s); ver != "" {
+		return strings.Replace(ver, "_", ".", -1)
+	}
+	return ""
+}
+
+func BenchmarkFindVersion(b *testing.B) {
+	const s = "Intel Mac OS X 10_15_7"
+	var ver string
+	b.Run("Regexp", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ver = findVersionRegexp(s)
+		}
+	})
+	b.Run("Scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ver = findVersion(s)
+		}
+	})
+	_ = ver
+}
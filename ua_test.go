@@ -1,7 +1,10 @@
 package useragent_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -19,11 +22,18 @@ var testTable = [][]string{
 	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.130 Safari/537.36 Edg/79.0.309.71", "Edge", "79.0.309.71", "desktop", "macOS"},
 
 	// Windows
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Vivaldi/6.5.3206.48", ua.Vivaldi, "6.5.3206.48", "desktop", ua.Windows},
 	{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", ua.Chrome, "59.0.3071.115", "desktop", "Windows"},
 	{"Mozilla/4.0 (compatible; MSIE 8.0; Windows NT 6.1; WOW64; Trident/4.0; SLCC2; .NET CLR 2.0.50727; .NET CLR 3.5.30729; .NET CLR 3.0.30729; Media Center PC 6.0; .NET4.0C; .NET4.0E; InfoPath.2; GWX:RED)", ua.InternetExplorer, "8.0", "desktop", "Windows"},
 	{"Mozilla/4.0 (compatible; MSIE 6.0; Windows NT 5.1; SV1; .NET CLR 1.1.4322) NS8/0.9.6", ua.InternetExplorer, "6.0", "desktop", "Windows"},
+	{"Opera/9.80 (Windows NT 6.1; U; en) Presto/2.12.388 Version/12.16", ua.Opera, "12.16", "desktop", "Windows"},
 	{"Mozilla/5.0 (Windows NT 10.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/52.0.2743.116 Safari/537.36 Edge/15.15063", ua.Edge, "15.15063", "desktop", "Windows"},
 
+	// malformed
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64 AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "120.0.0.0", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64)] AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "120.0.0.0", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36 [", ua.Chrome, "114.0.0.0", "mobile", ua.Android, "SM-S908B"},
+
 	// iPhone
 	{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.Safari, "10.0", "mobile", "iOS", "iPhone"},
 	{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.1.30 (KHTML, like Gecko) CriOS/60.0.3112.89 Mobile/14F89 Safari/602.1", ua.Chrome, "60.0.3112.89", "mobile", "iOS", "iPhone"},
@@ -45,14 +55,28 @@ var testTable = [][]string{
 	{"Mozilla/5.0 (Android 4.3; Mobile; rv:54.0) Gecko/54.0 Firefox/54.0", ua.Firefox, "54.0", "mobile", "Android"},
 	{"Mozilla/5.0 (Linux; Android 4.3; GT-I9300 Build/JSS15J) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/55.0.2883.91 Mobile Safari/537.36 OPR/42.9.2246.119956", ua.Opera, "42.9.2246.119956", "mobile", ua.Android},
 	{"Opera/9.80 (Android; Opera Mini/28.0.2254/66.318; U; en) Presto/2.12.423 Version/12.16", ua.OperaMini, "28.0.2254/66.318", "mobile", "Android", ""},
+	{"Opera/9.80 (J2ME/MIDP; Opera Mini/7.1.32052/29.3417; U; en) Presto/2.8.119 Version/11.10", ua.OperaMini, "7.1.32052/29.3417", "mobile", ""},
+	{"Opera/9.80 (iPhone; Opera Mini/7.0.4/28.2555; U; fr) Presto/2.8.119 Version/11.10", ua.OperaMini, "7.0.4/28.2555", "mobile", ua.IOS, "iPhone"},
 	{"Mozilla/5.0 (Linux; U; Android 4.3; en-us; GT-I9300 Build/JSS15J) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "Android browser", "4.0", "mobile", "Android"},
 	{"Mozilla/5.0 (Linux; Android 10; ONEPLUS A6003) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/73.0.3683.0 Mobile Safari/537.36 EdgA/44.11.4.4140", ua.Edge, "44.11.4.4140", "mobile", "Android", "ONEPLUS A6003"},
 
+	// Samsung Browser on Android
 	{"Mozilla/5.0 (Linux; Android 6.0.1; SAMSUNG SM-A310F/A310FXXU2BQB1 Build/MMB29K) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/5.4 Chrome/51.0.2704.106 Mobile Safari/537.36", "Samsung Browser", "5.4", "mobile", "Android", "SAMSUNG SM-A310F"},
+	{"Mozilla/5.0 (Linux; Android 11; SAMSUNG SM-T870) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/14.0 Chrome/87.0.4280.141 Safari/537.36", "Samsung Browser", "14.0", "tablet", "Android", "SAMSUNG SM-T870"},
+	{"Mozilla/5.0 (Linux; Android 13; SAMSUNG SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36", "Samsung Browser", "23.0", "mobile", "Android", "SAMSUNG SM-S918B"},
+	{"Mozilla/5.0 (Linux; Android 13; SAMSUNG SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36 (Secret)", "Samsung Browser", "23.0", "mobile", "Android", "SAMSUNG SM-S918B"}, // appended tokens don't affect version
+	{"Mozilla/5.0 (Linux; Android 9; SAMSUNG SM-G960F) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser Version/9.2 Chrome/67.0.3396.87 Mobile Safari/537.36", "Samsung Browser", "9.2", "mobile", "Android", "SAMSUNG SM-G960F"},
+
 	{"Mozilla/5.0 (Linux; Android 9; LM-Q630) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.198 Mobile Safari/537.36", ua.Chrome, "86.0.4240.198", "mobile", "Android", "LM-Q630"},
 	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/534.24 (KHTML, like Gecko) Chrome/79.0.3945.147 Safari/534.24 XiaoMi/MiuiBrowser/12.11.5-gn", "Miui Browser", "12.11.5-gn", "mobile", ua.Linux},
 	{"Mozilla/5.0 (Linux; U; Android 11; ru-ru; Redmi Note 10S Build/RP1A.200720.011) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/89.0.4389.116 Mobile Safari/537.36 XiaoMi/MiuiBrowser/12.13.2-gn", "Miui Browser", "12.13.2-gn", "mobile", ua.Android, "Redmi Note 10S"},
 
+	// non-ASCII and percent-encoded device names must be kept intact
+	{"Mozilla/5.0 (Linux; Android 12; 小米 12 Build/SKQ1.211006.001) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", ua.Chrome, "120.0.0.0", "mobile", ua.Android, "小米 12"},
+	{"Mozilla/5.0 (Linux; Android 12; Téléphone Ü2) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", ua.Chrome, "120.0.0.0", "mobile", ua.Android, "Téléphone Ü2"},
+	{"Mozilla/5.0 (Linux; Android 12; Galaxy%20S21) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", ua.Chrome, "120.0.0.0", "mobile", ua.Android, "Galaxy%20S21"},
+
+	{"Mozilla/5.0 (Linux; Android 10; V1990A Build/QP1A.190711.020; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/68.0.3440.106 Mobile Safari/537.36 SogouMobileBrowser/5.28.12", ua.SogouBrowser, "5.28.12", "mobile", ua.Android, "V1990A"},
 	{"Mozilla/5.0 (Linux; Android 10; MED-LX9N; HMSCore 6.6.0.311) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/92.0.4515.105 HuaweiBrowser/12.1.0.303 Mobile Safari/537.36", "Huawei Browser", "12.1.0.303", "mobile", "Android"},
 
 	// useragent, name, version, mobile, os
@@ -62,20 +86,38 @@ var testTable = [][]string{
 	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003 Build/PKQ1.180716.001) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.110 Mobile Safari/537.36 OPR/49.2.2361.134358", ua.Opera, "49.2.2361.134358", "mobile", ua.Android},
 	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003 Build/PKQ1.180716.001) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.86 Mobile Safari/537.36 EdgA/42.0.92.2864", ua.Edge, "42.0.92.2864", "mobile", ua.Android},
 	{"Mozilla/5.0 (Linux; Android 9; ONEPLUS A6003 Build/PKQ1.180716.001) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/71.0.3578.99 Mobile Safari/537.36 OPT/1.14.51", ua.OperaTouch, "1.14.51", "mobile", ua.Android},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36 Vivaldi/6.5.3216.57", ua.Vivaldi, "6.5.3216.57", "mobile", ua.Android, "SM-S908B"},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36 OPX/2.1.0", ua.OperaGX, "2.1.0", "mobile", ua.Android, "SM-S908B"},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36 OPRGX/2.1.0", ua.OperaGX, "2.1.0", "mobile", ua.Android, "SM-S908B"},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36 OPR/76.2.4027.73374", ua.Opera, "76.2.4027.73374", "mobile", ua.Android, "SM-S908B"},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.0.0 Mobile Safari/537.36 OPT/4.1.1", ua.OperaTouch, "4.1.1", "mobile", ua.Android, "SM-S908B"},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/57.0.2987.110 Safari/537.36 OPR/44.0.2510.1449 (Edition Neon)", ua.OperaNeon, "44.0.2510.1449", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/101.0.4951.54 Safari/537.36 OPR/87.0.4390.36 (Edition Crypto)", ua.OperaCrypto, "87.0.4390.36", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Linux; U; Android 13; SM-S908B Build/TP1A.220624.014) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Mobile Safari/537.36 OPR/76.0.2254.72391 Opera Mini/76.0.2254/72391", ua.OperaMini, "76.0.2254/72391", "mobile", ua.Android},
 	{"Mozilla/5.0 (Linux; Android 7.0; Moto G (4)) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4143.7 Mobile Safari/537.36 Chrome-Lighthouse", ua.Chrome, "84.0.4143.7", "mobile", ua.Android, "Moto G"},
 	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/87.0.4280.88 Safari/537.36", ua.Chrome, "87.0.4280.88", "desktop", ua.MacOS}, // Lighthouse
 	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4143.7 Safari/537.36 Chrome-Lighthouse", ua.Chrome, "84.0.4143.7", "desktop", ua.MacOS},
 	{"Mozilla/5.0 (Linux; Android 7.0; Moto G (4)) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4143.7 Mobile Safari/537.36 Chrome-Lighthouse", ua.Chrome, "84.0.4143.7", "mobile", ua.Android},
 
+	// Tizen
+	{"Mozilla/5.0 (Linux; Tizen 2.3; SAMSUNG SM-Z130H) AppleWebKit/537.3 (KHTML, like Gecko) SamsungBrowser/1.0 Mobile Safari/537.3", "Samsung Browser", "1.0", "mobile", ua.Tizen, "SAMSUNG SM-Z130H"},
+
 	// Windows phone
 	{"Mozilla/4.0 (compatible; MSIE 7.0; Windows Phone OS 7.0; Trident/3.1; IEMobile/7.0; NOKIA; Lumia 630)", ua.InternetExplorer, "7.0", "mobile", ua.WindowsPhone},
 
+	// Netscape
+	{"Mozilla/4.79 [en] (Windows NT 5.0; U)", ua.Netscape, "4.79", "desktop", ua.Windows},
+	{"Mozilla/4.7 [en] (Win98; I)", ua.Netscape, "4.7", "desktop", ""},
+
 	// FreeBSD
 	{"Mozilla/5.0 (compatible; Konqueror/4.5; FreeBSD) KHTML/4.5.4 (like Gecko)", "Konqueror", "4.5", "desktop", "FreeBSD"},
 
 	// Bots
 	{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.96 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.Googlebot, "2.1", "mobile", "Android", "Nexus 5X"},
 	{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.Googlebot, "2.1", "bot", ""},
+	// evergreen Googlebot must win over Chrome and Safari tokens
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/120.0.6099.216 Safari/537.36", ua.Googlebot, "2.1", "desktop", ""},
+	{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.216 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.Googlebot, "2.1", "mobile", ua.Android, "Nexus 5X"},
 	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.1 Safari/605.1.15 (Applebot/0.1; +http://www.apple.com/go/applebot)", "Applebot", "0.1", "bot", ""},
 	{"Twitterbot/1.0", ua.Twitterbot, "1.0", ua.Applebot, ""},
 	{"facebookexternalhit/1.1", ua.FacebookExternalHit, "1.1", "bot", ""},
@@ -114,6 +156,74 @@ var testTable = [][]string{
 	{"Mozilla/5.0 (iPhone; CPU iPhone OS 15_5 like Mac OS ) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 musical_ly_28.2.0 JsSdk/2.0 NetType/WIFI Channel/App Store ByteLocale/es Region/PE RevealType/Dialog isDarkMode/0 WKWebView/1 BytedanceWebview/d8a21c6 FalconTag/D6EBBF89-6D75-4BBD-9304-BF199C6B4DB1", ua.TiktokApp, "", "mobile", ua.IOS},
 	{"Mozilla/5.0 (Linux; Android 10; AGS3K-W09 Build/HUAWEIAGS3K-W09; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/88.0.4324.93 Safari/537.36 trill_2022803040 JsSdk/1.0 NetType/WIFI Channel/huaweiadsglobal_int AppName/musical_ly app_version/28.3.4 ByteLocale/es ByteFullLocale/es Region/PE BytedanceWebview/d8a21c6", ua.TiktokApp, "28.3.4", ua.Android},
 
+	// Yandex Browser
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 YaBrowser/23.9.0.0 Safari/537.36", ua.YandexBrowser, "23.9.0.0", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Linux; arm_64; Android 13; SM-A525F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.5845.967 YaBrowser/23.9.2.96.00 SA/3 Mobile Safari/537.36", ua.YandexBrowser, "23.9.2.96.00", "mobile", ua.Android},
+
+	// Whale
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Whale/3.23.214.10 Safari/537.36", ua.Whale, "3.23.214.10", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S911N) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Whale/2.10.2.2 Mobile Safari/537.36", ua.Whale, "2.10.2.2", "mobile", ua.Android}, // both tokens, Whale wins
+
+	// UC Browser
+	{"Mozilla/5.0 (Linux; U; Android 10; en-US; RMX1911 Build/QKQ1.200209.002) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/78.0.3904.108 UCBrowser/13.4.0.1306 Mobile Safari/537.36", ua.UCBrowser, "13.4.0.1306", "mobile", ua.Android, "RMX1911"},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 UBrowser/7.0.185.1002 Safari/537.36", ua.UCBrowser, "7.0.185.1002", "desktop", ua.Windows},
+
+	// HarmonyOS
+	{"Mozilla/5.0 (Linux; Android 10; HarmonyOS; NOH-AN00; HMSCore 6.11.0.302) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/99.0.4844.88 HuaweiBrowser/14.0.1.300 Mobile Safari/537.36", "Huawei Browser", "14.0.1.300", "mobile", ua.HarmonyOS, "NOH-AN00"},
+	{"Mozilla/5.0 (Linux; HarmonyOS 2.0.0; ELS-AN00) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/99.0.4844.88 Mobile Safari/537.36", ua.Chrome, "99.0.4844.88", "mobile", ua.HarmonyOS, "ELS-AN00"},
+
+	// KaiOS
+	{"Mozilla/5.0 (Mobile; LYF/F300B/LYF-F300B-001-01-15-130718-i; Android; rv:48.0) Gecko/48.0 Firefox/48.0 KAIOS/2.5", ua.Firefox, "48.0", "mobile", ua.KaiOS},
+	{"Mozilla/5.0 (Mobile; Nokia_8110_4G; rv:48.0) Gecko/48.0 Firefox/48.0 KAIOS/2.5", ua.Firefox, "48.0", "mobile", ua.KaiOS},
+
+	// Fuchsia
+	{"Mozilla/5.0 (Fuchsia) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/113.0.0.0 Safari/537.36", ua.Chrome, "113.0.0.0", "", ua.Fuchsia},
+
+	// Fire OS
+	{"Mozilla/5.0 (Linux; Android 9; KFONWI) AppleWebKit/537.36 (KHTML, like Gecko) Silk/92.2.11 like Chrome/92.0.4515.159 Safari/537.36", ua.AmazonSilk, "92.2.11", "tablet", ua.FireOS, "KFONWI"},
+	{"Mozilla/5.0 (Linux; Android 4.4.4; SD4930UR Build/KTU84P) AppleWebKit/537.36 (KHTML, like Gecko) Silk/3.67 like Chrome/37.0.2026.117 Mobile Safari/537.36", ua.AmazonSilk, "3.67", "mobile", ua.FireOS, "SD4930UR"},
+	{"Mozilla/5.0 (Linux; U; Android 4.0.3; en-us; KFTT Build/IML74K) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "Android browser", "4.0", "tablet", ua.FireOS, "KFTT"},
+
+	// Game launchers
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) EpicGamesLauncher/15.17.1-27123890+++Portal+Release-Live UnrealEngine/4.23.0-27123890+++Portal+Release-Live Chrome/90.0.4430.212 Safari/537.36", ua.EpicGames, "15.17.1", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) GOGGalaxyClient/2.0.67.2 Chrome/86.0.4240.198 Safari/537.36", ua.GOGGalaxy, "2.0.67.2", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows; U; Windows NT 10.0; en-US; Valve Steam Client/default/1596241936; ) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.117 Safari/537.36", ua.SteamApp, "", "desktop", ua.Windows},
+
+	// Apps
+	{"Mozilla/5.0 (Linux; Android 13; SM-G991B Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/119.0.6045.163 Mobile Safari/537.36 Telegram-Android/10.3.2 (Samsung SM-G991B; Android 13; SDK 33; AVERAGE)", ua.TelegramApp, "10.3.2", "mobile", ua.Android, "SM-G991B"},
+	{"Mozilla/5.0 (Linux; Android 10; V1990A Build/QP1A.190711.020; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/62.0.3202.84 Mobile Safari/537.36 SogouSearch Android1.0 version3.0 AppVersion/5909", ua.SogouApp, "5909", "mobile", ua.Android, "V1990A"},
+	{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 wp-iphone/23.6", ua.WordPressApp, "23.6", "mobile", ua.IOS, "iPhone"},
+	{"Mozilla/5.0 (Linux; Android 14; Pixel 8 Build/UD1A.230803.041; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.43 Mobile Safari/537.36 jetpack-android/23.8", ua.JetpackApp, "23.8", "mobile", ua.Android, "Pixel 8"},
+
+	// WebViews
+	{"Mozilla/5.0 (Linux; Android 10; SM-G973F Build/QP1A.190711.020; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/81.0.4044.138 Mobile Safari/537.36", ua.Chrome, "81.0.4044.138", "mobile", ua.Android, "SM-G973F"},
+	{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 EdgA/120.0.2210.115 BingSapphire/28.1.430124300", ua.Edge, "120.0.2210.115", "mobile", ua.Android, "Pixel 8"},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91 Copilot/1.0", ua.Edge, "120.0.2210.91", "desktop", ua.Windows},
+
+	// TV apps and browsers
+	{"Roku/DVP-12.5 (12.5.0.4174-88) Netflix/5.2", "Netflix", "5.2", "", ""},
+	{"Netflix/15.16.0 (AppleTV; tvOS 16.1; Scale/1.00)", "Netflix", "15.16.0", "", ""},
+	{"Dalvik/2.1.0 (Linux; U; Android 9; SHIELD Android TV Build/PPR1.180610.011)", "Dalvik", "2.1.0", "", ua.Android, "SHIELD Android TV"},
+	{"Mozilla/5.0 (Linux; Android 9; SHIELD Android TV Build/PPR1.180610.011; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.5735.196 Mobile Safari/537.36", ua.Chrome, "114.0.5735.196", "", ua.Android, "SHIELD Android TV"},
+	{"Opera/9.80 (Linux mips; Opera TV Store/5599; U; en) Presto/2.12.362 Version/12.50", ua.Opera, "12.50", "", ua.Linux},
+	{"HbbTV/1.2.1 (;Panasonic;VIERA 2013;3.672;4101-0003 0002-0000;)", "HbbTV", "1.2.1", "", ""},
+
+	// iOS browsers and iPad in desktop mode
+	{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1", ua.Chrome, "120.0.6099.119", "mobile", ua.IOS, "iPhone"},
+	{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Mobile/15E148 Safari/605.1.15", ua.Firefox, "121.0", "mobile", ua.IOS, "iPhone"},
+	{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 EdgiOS/120.2210.150 Mobile/15E148 Safari/605.1.15", ua.Edge, "120.2210.150", "mobile", ua.IOS, "iPhone"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.2 Mobile/15E148 Safari/604.1", ua.Safari, "13.1.2", "tablet", ua.IOS, "iPad"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Safari/604.1", ua.Chrome, "120.0.6099.119", "tablet", ua.IOS, "iPad"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Safari/605.1.15", ua.Firefox, "121.0", "tablet", ua.IOS, "iPad"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", ua.Safari, "17.1", "desktop", ua.MacOS},
+
+	// engines and automation
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", ua.Firefox, "121.0", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/64.0.3282.140 Safari/537.36 Edge/18.17763", ua.Edge, "18.17763", "desktop", ua.Windows},
+	{"Opera/9.80 (Windows NT 6.1; WOW64) Presto/2.12.388 Version/12.18", ua.Opera, "12.18", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Selenium-WebDriver/4.16", ua.Chrome, "120.0.0.0", "desktop", ua.Windows},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91 Microsoft-WebDriver/120.0.2210.91", ua.Edge, "120.0.2210.91", "desktop", ua.Windows},
+
 	// other
 	{"Mozilla/5.0 (X11; CrOS x86_64 14150.74.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.114 Safari/537.36", ua.Chrome, "94.0.4606.114", "desktop", ua.ChromeOS},
 	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/56.0.2924.87 Safari/537.36 Google (+https://developers.google.com/+/web/snippet/)", ua.Chrome, "56.0.2924.87", "bot", ua.Linux}, // Google+ fetch
@@ -124,6 +234,30 @@ var testTable = [][]string{
 	{"Wget/1.12 (linux-gnu)", "Wget", "1.12", "", ""},
 	{"Wget/1.17.1 (darwin15.2.0)", "Wget", "1.17.1", "", ""},
 	{"Seafile/9.0.2 (Linux)", "Seafile", "9.0.2", "", "Linux"},
+	{"PostmanRuntime/7.36.0", ua.Postman, "7.36.0", "", ""},
+	{"insomnia/2023.5.8", ua.Insomnia, "2023.5.8", "", ""},
+	{"Thunder Client (https://www.thunderclient.com)", ua.ThunderClient, "", "", ""},
+	{"Microsoft Office/16.0 (Windows NT 10.0; Microsoft Outlook 16.0.4266; Pro)", ua.MicrosoftOffice, "16.0", "desktop", ua.Windows},
+	{"Mozilla/4.0 (compatible; ms-office; MSOffice 16)", ua.MicrosoftOffice, "16", "", ""},
+	{"Microsoft Office Word 2014", ua.MicrosoftOffice, "2014", "", ""},
+	{"curl/8.1.0 libcurl/8.1.0 OpenSSL/3.0 zlib/1.2", ua.Curl, "8.1.0", "", ""},
+	{"curl/7.64.1", ua.Curl, "7.64.1", "", ""},
+	{"Wget/1.21.3", ua.Wget, "1.21.3", "", ""},
+	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/534.34 (KHTML, like Gecko) wkhtmltopdf/0.12.6 Safari/534.34", ua.Wkhtmltopdf, "0.12.6", "desktop", ua.Linux},
+	{"Mozilla/5.0 (Unknown; Linux x86_64) AppleWebKit/534.34 (KHTML, like Gecko) wkhtmltopdf Safari/534.34", ua.Wkhtmltopdf, "", "desktop", ua.Linux},
+	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) QtWebEngine/5.15.2 Chrome/83.0.4103.122 Safari/537.36", ua.QtWebEngine, "5.15.2", "desktop", ua.Linux},
+	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Falkon/3.2.0 QtWebEngine/5.15.8 Chrome/87.0.4280.144 Safari/537.36", "Falkon", "3.2.0", "desktop", ua.Linux},
+	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) qutebrowser/2.5.2 QtWebEngine/5.15.2 Chrome/87.0.4280.144 Safari/537.36", "qutebrowser", "2.5.2", "desktop", ua.Linux},
+	{"Prince/15.1 (www.princexml.com)", ua.Prince, "15.1", "", ""},
+	{"OpenAI/Python 1.3.0", ua.OpenAISDK, "1.3.0", "", ""},
+	{"Anthropic/Python 0.18.1", ua.AnthropicSDK, "0.18.1", "", ""},
+	{"Anthropic/Python", ua.AnthropicSDK, "", "", ""},
+
+	// health checks
+	{"ELB-HealthChecker/2.0", "ELB-HealthChecker", "2.0", "", ""},
+	{"kube-probe/1.27", "kube-probe", "1.27", "", ""},
+	{"GoogleHC/1.0", "GoogleHC", "1.0", "", ""},
+	{"Amazon CloudFront", "Amazon CloudFront", "", "", ""},
 
 	// unstandard stuff
 	{"BUbiNG (+http://law.di.unimi.it/BUbiNG.html)", "BUbiNG", "", "", ""},
@@ -210,6 +344,1460 @@ func TestParse(t *testing.T) {
 	}
 }
 
+var botTable = []struct {
+	ua      string
+	name    string
+	version string
+	url     string
+}{
+	{"Mozilla/5.0 (compatible; Yeti/1.1; +http://naver.me/spd)", ua.Yeti, "1.1", "http://naver.me/spd"},
+	{"Mozilla/5.0 (compatible; Daum/4.1; +http://cs.daum.net/faq/15/4118.html?faqId=28966)", ua.Daum, "4.1", "http://cs.daum.net/faq/15/4118.html?faqId=28966"},
+	{"Mozilla/5.0 (compatible; Daumoa/4.0; +http://cs.daum.net/faq/15/4118.html?faqId=28966)", ua.Daum, "4.0", "http://cs.daum.net/faq/15/4118.html?faqId=28966"},
+	{"Mozilla/5.0 (compatible; Google-InspectionTool/1.0)", ua.GoogleInspectionTool, "1.0", ""},
+	{"Mozilla/5.0 (Linux; Android 7.0; SM-G930V Build/NRD90M) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.125 Mobile Safari/537.36 (compatible; Google-Read-Aloud; +https://support.google.com/webmasters/answer/1061943)", ua.GoogleReadAloud, "", "https://support.google.com/webmasters/answer/1061943"},
+	{"google-speakr", ua.GoogleReadAloud, "", ""},
+	{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.199 Mobile Safari/537.36 (compatible; Google-InspectionTool/1.0)", ua.GoogleInspectionTool, "1.0", ""},
+	{"Mozilla/5.0 (compatible; Google-Structured-Data-Testing-Tool +https://search.google.com/structured-data/testing-tool)", ua.GoogleStructuredDataTestingTool, "", ""},
+	{"Mozilla/5.0 (Linux;u;Android 4.2.2;zh-cn;) AppleWebKit/534.46 (KHTML,like Gecko) Version/5.1 Mobile Safari/10600.6.3 (compatible; Baiduspider/2.0; +http://www.baidu.com/search/spider.html)", ua.Baiduspider, "2.0", "http://www.baidu.com/search/spider.html"},
+	{"DuckDuckBot/1.1; (+http://duckduckgo.com/duckduckbot.html)", ua.DuckDuckBot, "1.1", "http://duckduckgo.com/duckduckbot.html"},
+	{"TelegramBot (like TwitterBot)", ua.TelegramBot, "", ""},
+	{"Mozilla/5.0 (Windows NT 6.1; WOW64) SkypeUriPreview Preview/0.5 skype-url-preview@microsoft.com", ua.SkypeURIPreview, "0.5", ""},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/107.0.0.0 Safari/537.36 Edg/107.0.1418.62 MicrosoftPreview/2.0 +https://aka.ms/MicrosoftPreview", ua.MicrosoftPreview, "2.0", "https://aka.ms/MicrosoftPreview"},
+	{"Mozilla/5.0 (compatible; Microsoft-Preview/1.0)", ua.MicrosoftPreview, "1.0", ""},
+	{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", ua.FacebookExternalHit, "1.1", "http://www.facebook.com/externalhit_uatext.php"},
+	{"facebookcatalog/1.0", ua.FacebookCatalog, "1.0", ""},
+	{"WordPress/6.4.2; https://example.com", ua.WordPress, "6.4.2", "https://example.com"},
+	{"Mozilla/5.0 (compatible; Bluesky Cardyb/1.1; +mailto:support@bsky.app)", ua.Bluesky, "1.1", ""},
+	{"Mozilla/5.0 (compatible; Google-Apps-Script; beanserver; +https://script.google.com; id: UAEmdDd-6ZzWjyjj6f8R7e6L3e9LTT6H9rA)", ua.GoogleAppsScript, "", "https://script.google.com"},
+	{"Mozilla/5.0 (compatible; GoogleDocs; apps-spreadsheets; +http://docs.google.com)", ua.GoogleDocs, "", "http://docs.google.com"},
+	{"Mozilla/5.0 (compatible; NovelCrawler/3.2; +https://novel.example/crawler)", "NovelCrawler", "3.2", "https://novel.example/crawler"},
+	{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.216 Mobile Safari/537.36 (compatible; NovelCrawler/3.2; +https://novel.example/crawler)", "NovelCrawler", "3.2", "https://novel.example/crawler"},
+	{"Mozilla/5.0 (Linux; U; Android 4.3; en-us; GT-I9300 Build/JSS15J) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30 (compatible; NovelCrawler/3.2; +https://novel.example/crawler)", "NovelCrawler", "3.2", "https://novel.example/crawler"},
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/120.0.6099.216 Safari/537.36", ua.Googlebot, "2.1", "http://www.google.com/bot.html"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.1 Safari/605.1.15 (Applebot/0.1; +http://www.apple.com/go/applebot)", ua.Applebot, "0.1", "http://www.apple.com/go/applebot"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.1 Safari/605.1.15 (Applebot-Extended/0.1; +http://www.apple.com/go/applebot)", ua.ApplebotExtended, "0.1", "http://www.apple.com/go/applebot"},
+	// social network link previews
+	{"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", ua.Slackbot, "1.0", "https://api.slack.com/robots"},
+	{"WhatsApp/2.23.20.0 A", ua.WhatsApp, "2.23.20.0", ""},
+	{"LinkedInBot/1.0 (compatible; Mozilla/5.0; Apache-HttpClient +http://www.linkedin.com)", ua.LinkedInBot, "1.0", ""},
+	{"Pinterest/0.2 (+https://www.pinterest.com/bot.html)", ua.Pinterest, "0.2", "https://www.pinterest.com/bot.html"},
+	// AI crawlers
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.2; +https://openai.com/gptbot)", ua.GPTBot, "1.2", "https://openai.com/gptbot"},
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko); compatible; ChatGPT-User/1.0; +https://openai.com/bot", ua.ChatGPTUser, "1.0", "https://openai.com/bot"},
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko); compatible; OAI-SearchBot/1.0; +https://openai.com/searchbot", ua.OAISearchBot, "1.0", "https://openai.com/searchbot"},
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; ClaudeBot/1.0; +claudebot@anthropic.com)", ua.ClaudeBot, "1.0", ""},
+	{"Mozilla/5.0 (compatible; anthropic-ai/1.0; +http://www.anthropic.com/bot.html)", ua.AnthropicAI, "1.0", "http://www.anthropic.com/bot.html"},
+	{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; PerplexityBot/1.0; +https://perplexity.ai/perplexitybot)", ua.PerplexityBot, "1.0", "https://perplexity.ai/perplexitybot"},
+	{"CCBot/2.0 (https://commoncrawl.org/faq/)", ua.CCBot, "2.0", "https://commoncrawl.org/faq/"},
+	{"Mozilla/5.0 (compatible; Google-Extended)", ua.GoogleExtended, "", ""},
+	// Chinese crawlers
+	{"Sogou web spider/4.0(+http://www.sogou.com/docs/help/webmasters.htm#07)", ua.SogouSpider, "4.0", "http://www.sogou.com/docs/help/webmasters.htm#07"},
+	{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.81 YisouSpider/5.0 Safari/537.36", ua.YisouSpider, "5.0", ""},
+	{"Mozilla/5.0 (Linux; Android 5.0) AppleWebKit/537.36 (KHTML, like Gecko) Mobile Safari/537.36 (compatible; Bytespider; spider-feedback@bytedance.com)", ua.Bytespider, "", ""},
+	// uptime monitors
+	{"Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)", ua.UptimeRobot, "2.0", "http://www.uptimerobot.com/"},
+	{"Pingdom.com_bot_version_1.4_(http://www.pingdom.com/)", ua.Pingdom, "1.4", "http://www.pingdom.com/"},
+}
+
+func TestBots(t *testing.T) {
+	for _, test := range botTable {
+		agent := ua.Parse(test.ua)
+		if agent.Name != test.name {
+			t.Error("\n", test.ua, "\nName should be", test.name, "not", agent.Name)
+		}
+		if agent.Version != test.version {
+			t.Error("\n", test.ua, "\nVersion should be", test.version, "not", agent.Version)
+		}
+		if agent.URL != test.url {
+			t.Error("\n", test.ua, "\nURL should be", test.url, "not", agent.URL)
+		}
+		if !agent.Bot {
+			t.Error("\n", test.ua, "should be bot")
+		}
+	}
+}
+
+func TestChannel(t *testing.T) {
+	tests := []struct {
+		ua      string
+		channel string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:109.0) Gecko/20100101 Firefox/109.0b4", ua.ChannelBeta},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0a1", ua.ChannelNightly},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:120.0) Gecko/20100101 Firefox/120.0", ua.ChannelStable},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:115.0) Gecko/20100101 Firefox/115.0", ua.ChannelStable}, // ESR can't be told apart from stable
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.6200.0 Safari/537.36 Canary", ua.ChannelCanary},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.6167.8 Safari/537.36 Dev", ua.ChannelDev},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.6167.16 Safari/537.36 Beta", ua.ChannelBeta},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Channel != test.channel {
+			t.Error("\n", test.ua, "\nChannel should be", test.channel, "not", agent.Channel)
+		}
+	}
+}
+
+func TestWithVersionComponents(t *testing.T) {
+	tests := []struct {
+		ua      string
+		version string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.12; rv:54.0) Gecko/20100101 Firefox/54.0", "54.0.0"},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", "59.0.3071"},
+		{"Mozilla/5.0 (Linux; Android 10;)", ""},
+		{"PostmanRuntime/dev", "dev"},
+	}
+	p := ua.New(ua.WithVersionComponents(3))
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Version != test.version {
+			t.Error("\n", test.ua, "\nVersion should be", test.version, "not", agent.Version)
+		}
+	}
+}
+
+func TestLegacyDevice(t *testing.T) {
+	tests := []struct {
+		ua     string
+		brand  string
+		device string
+	}{
+		{"SonyEricssonK310iv/R4DA Browser/NetFront/3.3 Profile/MIDP-2.0 Configuration/CLDC-1.1 UP.Link/6.3.1.13.0", "Sony Ericsson", "K310iv"},
+		{"SAMSUNG-SGH-E250/1.0 Profile/MIDP-2.0 Configuration/CLDC-1.1 UP.Browser/6.2.3.3.c.1.101 (GUI) MMP/2.0", "Samsung", "SGH-E250"},
+		{"LG-KU990/V10a Browser/Obigo-Q05A/3.6 MIDP-2.0/CLDC-1.1", "LG", "KU990"},
+		{"MOT-V3/0E.40.3CR MIB/2.2.1 Profile/MIDP-2.0 Configuration/CLDC-1.1", "Motorola", "V3"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Brand != test.brand {
+			t.Error("\n", test.ua, "\nBrand should be", test.brand, "not", agent.Brand)
+		}
+		if agent.Device != test.device {
+			t.Error("\n", test.ua, "\nDevice should be", test.device, "not", agent.Device)
+		}
+		if !agent.Mobile {
+			t.Error("\n", test.ua, "should be mobile")
+		}
+	}
+}
+
+func TestBotKind(t *testing.T) {
+	tests := []struct {
+		ua   string
+		kind string
+	}{
+		{"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", ua.BotCrawler},
+		{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", ua.BotPreview},
+		{"Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)", ua.BotMonitor},
+		{"Pingdom.com_bot_version_1.4_(http://www.pingdom.com/)", ua.BotMonitor},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.2; +https://openai.com/gptbot)", ua.BotAI},
+		{"Mozilla/5.0 (compatible; SomeBot/1.0; +http://example.com/bot)", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ""},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.BotKind != test.kind {
+			t.Error("\n", test.ua, "\nBotKind should be", test.kind, "not", agent.BotKind)
+		}
+	}
+}
+
+func TestEngine(t *testing.T) {
+	tests := []struct {
+		ua     string
+		engine string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1", ua.EngineWebKit},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Mobile/15E148 Safari/605.1.15", ua.EngineWebKit},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 EdgiOS/120.2210.150 Mobile/15E148 Safari/605.1.15", ua.EngineWebKit},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.EngineBlink},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", ua.EngineGecko},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/64.0.3282.140 Safari/537.36 Edge/18.17763", ua.EngineEdgeHTML},
+		{"Opera/9.80 (Windows NT 6.1; WOW64) Presto/2.12.388 Version/12.18", ua.EnginePresto},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", ua.EngineWebKit},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.Engine != test.engine {
+			t.Error("\n", test.ua, "\nEngine should be", test.engine, "not", agent.Engine)
+		}
+	}
+}
+
+func TestSmartHub(t *testing.T) {
+	const smartHub = "Mozilla/5.0 (SmartHub; SMART-TV; U; Linux/SmartTV; Maple2012) AppleWebKit/534.7 (KHTML, like Gecko) SmartTV Safari/534.7"
+	agent := ua.Parse(smartHub)
+	if agent.Name != ua.SmartHub || agent.Brand != "Samsung" {
+		t.Error("\n", smartHub, "\nshould be Samsung SmartHub not", agent.Brand, agent.Name)
+	}
+	if !agent.TV || !agent.App || agent.Mobile || agent.Desktop {
+		t.Error("\n", smartHub, "\nshould be TV app, got", agent.DeviceType())
+	}
+}
+
+func TestIsOS(t *testing.T) {
+	tests := []struct {
+		ua           string
+		windows      bool
+		macOS        bool
+		linux        bool
+		chromeOS     bool
+		windowsPhone bool
+		blackBerry   bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true, false, false, false, false, false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", false, true, false, false, false, false},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", false, false, true, false, false, false},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false, false, false, true, false, false},
+		{"Mozilla/4.0 (compatible; MSIE 7.0; Windows Phone OS 7.0; Trident/3.1; IEMobile/7.0; LG; GW910)", false, false, false, false, true, false},
+		{"Mozilla/5.0 (BlackBerry; U; BlackBerry 9900; en) AppleWebKit/534.11+ (KHTML, like Gecko) Version/7.1.0.346 Mobile Safari/534.11+", false, false, false, false, false, true},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.IsWindows() != test.windows {
+			t.Error("\n", test.ua, "\nIsWindows should be", test.windows)
+		}
+		if agent.IsMacOS() != test.macOS {
+			t.Error("\n", test.ua, "\nIsMacOS should be", test.macOS)
+		}
+		if agent.IsLinux() != test.linux {
+			t.Error("\n", test.ua, "\nIsLinux should be", test.linux)
+		}
+		if agent.IsChromeOS() != test.chromeOS {
+			t.Error("\n", test.ua, "\nIsChromeOS should be", test.chromeOS)
+		}
+		if agent.IsWindowsPhone() != test.windowsPhone {
+			t.Error("\n", test.ua, "\nIsWindowsPhone should be", test.windowsPhone)
+		}
+		if agent.IsBlackBerry() != test.blackBerry {
+			t.Error("\n", test.ua, "\nIsBlackBerry should be", test.blackBerry)
+		}
+	}
+}
+
+func TestIsBrowser(t *testing.T) {
+	tests := []struct {
+		ua      string
+		chrome  bool
+		firefox bool
+		safari  bool
+		edge    bool
+		opera   bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true, false, false, false, false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", false, true, false, false, false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", false, false, true, false, false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91", false, false, false, true, false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0", false, false, false, false, true},
+		{"Opera/9.80 (Android; Opera Mini/28.0.2254/66.318; U; en) Presto/2.12.423 Version/12.16", false, false, false, false, true},
+		{"Mozilla/5.0 (Linux; Android 13; SM-S908B) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.0.0 Mobile Safari/537.36 OPT/4.1.1", false, false, false, false, true},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.IsChrome() != test.chrome {
+			t.Error("\n", test.ua, "\nIsChrome should be", test.chrome)
+		}
+		if agent.IsFirefox() != test.firefox {
+			t.Error("\n", test.ua, "\nIsFirefox should be", test.firefox)
+		}
+		if agent.IsSafari() != test.safari {
+			t.Error("\n", test.ua, "\nIsSafari should be", test.safari)
+		}
+		if agent.IsEdge() != test.edge {
+			t.Error("\n", test.ua, "\nIsEdge should be", test.edge)
+		}
+		if agent.IsOpera() != test.opera {
+			t.Error("\n", test.ua, "\nIsOpera should be", test.opera)
+		}
+	}
+}
+
+func TestIsSearchEngineCrawler(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want bool
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", true},
+		{"Mozilla/5.0 (compatible; YandexBot/3.0; +http://yandex.com/bots)", true},
+		{"Mozilla/5.0 (compatible; Yeti/1.1; +http://naver.me/spd)", true},
+		{"Mozilla/5.0 (compatible; bingbot/2.0)", true},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; bingbot/2.0) Chrome/116.0.1938.76 Safari/537.36", true},
+		{"Mozilla/5.0 (compatible; YandexBot/3.0)", true},
+		{"Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)", false},
+		{"Twitterbot/1.0", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+	}
+	for _, test := range tests {
+		if got := ua.Parse(test.ua).IsSearchEngineCrawler(); got != test.want {
+			t.Error("\n", test.ua, "\nIsSearchEngineCrawler should be", test.want, "not", got)
+		}
+	}
+}
+
+func TestApps(t *testing.T) {
+	for _, s := range []string{
+		"Mozilla/5.0 (Linux; Android 13; SM-G991B Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/119.0.6045.163 Mobile Safari/537.36 Telegram-Android/10.3.2 (Samsung SM-G991B; Android 13; SDK 33; AVERAGE)",
+		"Mozilla/5.0 (Linux; Android 10; V1990A Build/QP1A.190711.020; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/62.0.3202.84 Mobile Safari/537.36 SogouSearch Android1.0 version3.0 AppVersion/5909",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 wp-iphone/23.6",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8 Build/UD1A.230803.041; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.43 Mobile Safari/537.36 jetpack-android/23.8",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) EpicGamesLauncher/15.17.1-27123890+++Portal+Release-Live UnrealEngine/4.23.0-27123890+++Portal+Release-Live Chrome/90.0.4430.212 Safari/537.36",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) GOGGalaxyClient/2.0.67.2 Chrome/86.0.4240.198 Safari/537.36",
+		"Mozilla/5.0 (Windows; U; Windows NT 10.0; en-US; Valve Steam Client/default/1596241936; ) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.117 Safari/537.36",
+	} {
+		agent := ua.Parse(s)
+		if !agent.App || agent.Bot {
+			t.Error("\n", s, "should be app")
+		}
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	for _, s := range []string{
+		"ELB-HealthChecker/2.0",
+		"kube-probe/1.27",
+		"GoogleHC/1.0",
+		"Amazon CloudFront",
+	} {
+		if agent := ua.Parse(s); !agent.HealthCheck || !agent.Tool {
+			t.Error("\n", s, "should be health check tool")
+		}
+	}
+}
+
+func TestIsOutdated(t *testing.T) {
+	const (
+		oldIE     = "Mozilla/4.0 (compatible; MSIE 8.0; Windows NT 6.1; WOW64; Trident/4.0)"
+		chrome120 = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	)
+	if !ua.Parse(oldIE).IsOutdated() {
+		t.Error("\n", oldIE, "should be outdated")
+	}
+	if ua.Parse(chrome120).IsOutdated() {
+		t.Error("\n", chrome120, "should not be outdated")
+	}
+
+	p := ua.New(ua.WithMinVersions(map[string]ua.VersionNo{
+		ua.Chrome: {Major: 121},
+	}))
+	if !p.Parse(chrome120).IsOutdated() {
+		t.Error("\n", chrome120, "should be outdated when Chrome 121 is required")
+	}
+	if p.Parse(oldIE).IsOutdated() {
+		t.Error("\n", oldIE, "should not be outdated without IE threshold")
+	}
+}
+
+func TestParseComparable(t *testing.T) {
+	const s = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	if ua.Parse(s) != ua.New().Parse(s) {
+		t.Error("\n", s, "\nresults of different parsers should be equal")
+	}
+	if got := ua.Parse("curl/8.0"); got != (ua.UserAgent{
+		VersionNo: ua.VersionNo{Major: 8},
+		String:    "curl/8.0",
+		Name:      ua.Curl,
+		Version:   "8.0",
+		Tool:      true,
+	}) {
+		t.Errorf("curl/8.0 should equal struct literal, got %+v", got)
+	}
+}
+
+func TestIsOSOutdated(t *testing.T) {
+	const (
+		android7 = "Mozilla/5.0 (Linux; Android 7.0; SM-G930F Build/NRD90M) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/64.0.3282.137 Mobile Safari/537.36"
+		ios17    = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1"
+	)
+	if !ua.Parse(android7).IsOSOutdated() {
+		t.Error("\n", android7, "should have outdated OS")
+	}
+	if ua.Parse(ios17).IsOSOutdated() {
+		t.Error("\n", ios17, "should not have outdated OS")
+	}
+
+	p := ua.New(ua.WithMinOSVersions(map[string]ua.VersionNo{
+		ua.IOS: {Major: 18},
+	}))
+	if !p.Parse(ios17).IsOSOutdated() {
+		t.Error("\n", ios17, "should have outdated OS when iOS 18 is required")
+	}
+	if p.Parse(android7).IsOSOutdated() {
+		t.Error("\n", android7, "should not have outdated OS without Android threshold")
+	}
+}
+
+func TestSupportsHTTP2(t *testing.T) {
+	tests := []struct {
+		ua    string
+		http2 bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko", false},
+		{"Mozilla/5.0 (Linux; U; Android 4.0.3; ko-kr; LG-L160L Build/IML74K) AppleWebkit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", false},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/40.0.2214.115 Safari/537.36", false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/603.3.8 (KHTML, like Gecko) Version/10.1.2 Safari/603.3.8", true},
+	}
+	for _, test := range tests {
+		if got := ua.Parse(test.ua).SupportsHTTP2(); got != test.http2 {
+			t.Error("\n", test.ua, "\nexpected HTTP/2 support", test.http2, "got", got)
+		}
+	}
+}
+
+func TestScreenClass(t *testing.T) {
+	tests := []struct {
+		ua    string
+		class string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.ScreenSmall},
+		{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.ScreenMedium},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.ScreenLarge},
+		{"Mozilla/5.0 (Linux; Android 13; SM-F936B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", ua.ScreenMedium},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.ScreenClass != test.class {
+			t.Error("\n", test.ua, "\nScreenClass should be", test.class, "not", agent.ScreenClass)
+		}
+	}
+}
+
+func TestWebView(t *testing.T) {
+	tests := []struct {
+		ua      string
+		webView bool
+	}{
+		{"Mozilla/5.0 (Linux; Android 10; SM-G973F Build/QP1A.190711.020; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/81.0.4044.138 Mobile Safari/537.36", true},
+		{"Mozilla/5.0 (Linux; Android 13; SM-G991B Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/119.0.6045.163 Mobile Safari/537.36 Telegram-Android/10.3.2 (Samsung SM-G991B; Android 13; SDK 33; AVERAGE)", true},
+		{"Mozilla/5.0 (Linux; Android 10; SM-G973F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/81.0.4044.138 Mobile Safari/537.36", false},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 EdgA/120.0.2210.115 BingSapphire/28.1.430124300", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91 Copilot/1.0", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91", false},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.WebView != test.webView {
+			t.Error("\n", test.ua, "\nWebView should be", test.webView, "not", agent.WebView)
+		}
+	}
+}
+
+func TestOSFamily(t *testing.T) {
+	tests := []struct {
+		ua     string
+		family string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.FamilyApple},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/603.3.8 (KHTML, like Gecko) Version/10.1.2 Safari/603.3.8", ua.FamilyApple},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", ua.FamilyMicrosoft},
+		{"Mozilla/4.0 (compatible; MSIE 7.0; Windows Phone OS 7.0; Trident/3.1; IEMobile/7.0; NOKIA; Lumia 630)", ua.FamilyMicrosoft},
+		{"Mozilla/5.0 (Android 4.3; Mobile; rv:54.0) Gecko/54.0 Firefox/54.0", ua.FamilyAndroidLike},
+		{"Mozilla/5.0 (Phone; OpenHarmony 4.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36 ArkWeb/4.1.6.1 Mobile", ua.FamilyAndroidLike},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0", ua.FamilyUnix},
+		{"Mozilla/5.0 (compatible; Konqueror/4.5; FreeBSD) KHTML/4.5.4 (like Gecko)", ua.FamilyUnix},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.FamilyUnix},
+		{"Twitterbot/1.0", ""},
+	}
+	for _, test := range tests {
+		if got := ua.Parse(test.ua).OSFamily(); got != test.family {
+			t.Error("\n", test.ua, "\nOSFamily should be", test.family, "not", got)
+		}
+	}
+}
+
+func TestParseOS(t *testing.T) {
+	for _, test := range testTable {
+		want := ua.Parse(test[0])
+		if want.Bot {
+			continue
+		}
+		os, version := ua.ParseOS(test[0])
+		if os != want.OS || version != want.OSVersion {
+			t.Error("\n", test[0], "\nOS should be", want.OS, want.OSVersion, "not", os, version)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	const galaxyWatch = "Mozilla/5.0 (Linux; Tizen 4.0; SAMSUNG SM-R800) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/1.0 Chrome/56.0.2924.0 Mobile Safari/537.36"
+	agent := ua.Parse(galaxyWatch)
+	if agent.OS != ua.Tizen || agent.OSVersion != "4.0" {
+		t.Error("\n", galaxyWatch, "\nOS should be Tizen 4.0 not", agent.OS, agent.OSVersion)
+	}
+	if agent.Name != "Samsung Browser" {
+		t.Error("\n", galaxyWatch, "\nName should be Samsung Browser not", agent.Name)
+	}
+	if !agent.Watch || agent.Mobile || agent.Tablet {
+		t.Error("\n", galaxyWatch, "should be watch only")
+	}
+}
+
+func TestTV(t *testing.T) {
+	tests := []struct {
+		ua string
+		os string
+		tv bool
+	}{
+		{"Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36", ua.Tizen, true},
+		{"Mozilla/5.0 (Linux; Tizen 2.3; SmartTV) AppleWebKit/538.1 (KHTML, like Gecko) SamsungBrowser/1.0 TV Safari/538.1", ua.Tizen, true},
+		{"Mozilla/5.0 (Linux; Tizen 4.0; SAMSUNG SM-R800) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/1.0 Chrome/56.0.2924.0 Mobile Safari/537.36", ua.Tizen, false},
+		{"Opera/9.80 (Linux mips; Opera TV Store/5599; U; en) Presto/2.12.362 Version/12.11 OMI/3.0", ua.Linux, true},
+		{"Mozilla/5.0 (Linux; Android 9; SHIELD Android TV Build/PPR1.180610.011; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.5735.196 Mobile Safari/537.36", ua.Android, true},
+		{"Mozilla/5.0 (Linux; Android 7.1.2; AFTMM Build/NS6265; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/70.0.3538.110 Mobile Safari/537.36", ua.Android, true},
+		{"Mozilla/5.0 (X11; Linux armv7l) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/89.0.4389.90 Safari/537.36 CrKey/1.54.250320", ua.Linux, true},
+		{"Roku/DVP-9.10 (519.10E04111A)", "", true},
+		{"Mozilla/5.0 (Web0S; Linux/SmartTV) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.79 Safari/537.36 WebAppManager", ua.WebOS, true},
+		{"Mozilla/5.0 (Linux; webOS/2.2.4; U; en-US) AppleWebKit/534.6 (KHTML, like Gecko) webOSBrowser/221.56 Safari/534.6 Pre/3.0", ua.WebOS, false},
+		{"Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", ua.Android, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.OS != test.os {
+			t.Error("\n", test.ua, "\nOS should be", test.os, "not", agent.OS)
+		}
+		if agent.TV != test.tv {
+			t.Error("\n", test.ua, "\nTV should be", test.tv, "not", agent.TV)
+		}
+		if agent.TV && (agent.Mobile || agent.Tablet || agent.Desktop) {
+			t.Error("\n", test.ua, "should be TV only")
+		}
+	}
+}
+
+func TestConsole(t *testing.T) {
+	tests := []struct {
+		ua     string
+		device string
+	}{
+		{"Mozilla/5.0 (PlayStation; PlayStation 5/2.26) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0 Safari/605.1.15", "PlayStation 5"},
+		{"Mozilla/5.0 (PlayStation 4 3.11) AppleWebKit/537.73 (KHTML, like Gecko)", "PlayStation 4"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; Xbox; Xbox Series X) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/48.0.2564.82 Safari/537.36 Edge/20.02", "Xbox Series X"},
+		{"Mozilla/5.0 (Nintendo Switch; WifiWebAuthApplet) AppleWebKit/606.4 (KHTML, like Gecko) NF/6.0.1.15.4 NintendoBrowser/5.1.0.20393", "Nintendo Switch"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if !agent.Console {
+			t.Error("\n", test.ua, "\nshould be console")
+		}
+		if agent.Device != test.device {
+			t.Error("\n", test.ua, "\nDevice should be", test.device, "not", agent.Device)
+		}
+		if agent.Mobile || agent.Tablet || agent.Desktop {
+			t.Error("\n", test.ua, "should be console only")
+		}
+	}
+}
+
+func TestTVApps(t *testing.T) {
+	for _, s := range []string{
+		"Roku/DVP-12.5 (12.5.0.4174-88) Netflix/5.2",
+		"Netflix/15.16.0 (AppleTV; tvOS 16.1; Scale/1.00)",
+		"Dalvik/2.1.0 (Linux; U; Android 9; SHIELD Android TV Build/PPR1.180610.011)",
+	} {
+		if agent := ua.Parse(s); !agent.TV || !agent.App {
+			t.Error("\n", s, "\nshould be TV app")
+		}
+	}
+
+	// TV browsers
+	for _, s := range []string{
+		"Mozilla/5.0 (Linux; Android 9; SHIELD Android TV Build/PPR1.180610.011; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/114.0.5735.196 Mobile Safari/537.36",
+		"Opera/9.80 (Linux mips; Opera TV Store/5599; U; en) Presto/2.12.362 Version/12.50",
+		"HbbTV/1.2.1 (;Panasonic;VIERA 2013;3.672;4101-0003 0002-0000;)",
+	} {
+		if agent := ua.Parse(s); !agent.TV || agent.App {
+			t.Error("\n", s, "\nTV browser should not be app")
+		}
+	}
+}
+
+func TestOSInferred(t *testing.T) {
+	tests := []struct {
+		ua       string
+		os       string
+		inferred bool
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", ua.MacOS, false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.2 Mobile/15E148 Safari/604.1", ua.IOS, true},
+		{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.IOS, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.OS != test.os {
+			t.Error("\n", test.ua, "\nOS should be", test.os, "not", agent.OS)
+		}
+		if agent.OSInferred != test.inferred {
+			t.Error("\n", test.ua, "\nOSInferred should be", test.inferred, "not", agent.OSInferred)
+		}
+	}
+}
+
+func TestIPadDesktopMode(t *testing.T) {
+	tests := []struct {
+		ua   string
+		ipad bool
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.1.2 Mobile/15E148 Safari/604.1", true},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Safari/604.1", true},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Safari/605.1.15", true},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", false},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if ipad := agent.OS == ua.IOS && agent.Device == "iPad" && agent.Tablet && !agent.Desktop; ipad != test.ipad {
+			t.Error("\n", test.ua, "\niPad should be", test.ipad, "got", agent.OS, agent.Device, agent.DeviceClass())
+		}
+	}
+}
+
+func TestIsOSAtLeast(t *testing.T) {
+	const (
+		ios14 = "Mozilla/5.0 (iPhone; CPU iPhone OS 14_8 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.2 Mobile/15E148 Safari/604.1"
+		ios15 = "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1"
+	)
+	tests := []struct {
+		ua    string
+		os    string
+		major int
+		minor int
+		want  bool
+	}{
+		{ios14, ua.IOS, 15, 0, false},
+		{ios15, ua.IOS, 15, 0, true},
+		{ios14, ua.IOS, 14, 8, true},
+		{ios14, ua.IOS, 14, 9, false},
+		{ios15, ua.Android, 15, 0, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if got := agent.IsOSAtLeast(test.os, test.major, test.minor); got != test.want {
+			t.Errorf("\n%s\nIsOSAtLeast(%s, %d, %d) should be %v", test.ua, test.os, test.major, test.minor, test.want)
+		}
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	p := ua.New()
+	for _, test := range testTable {
+		want := p.Parse(test[0])
+		got := p.Acquire(test[0])
+		if *got != want {
+			t.Errorf("\n%s\nAcquire should be %+v\nnot %+v", test[0], want, *got)
+		}
+		p.Release(got)
+	}
+}
+
+func TestTools(t *testing.T) {
+	for _, s := range []string{
+		"PostmanRuntime/7.36.0",
+		"insomnia/2023.5.8",
+		"Thunder Client (https://www.thunderclient.com)",
+		"Microsoft Office/16.0 (Windows NT 10.0; Microsoft Outlook 16.0.4266; Pro)",
+		"Mozilla/4.0 (compatible; ms-office; MSOffice 16)",
+		"Microsoft Office Word 2014",
+		"curl/8.1.0 libcurl/8.1.0 OpenSSL/3.0 zlib/1.2",
+		"curl/7.64.1",
+		"Wget/1.21.3",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/534.34 (KHTML, like Gecko) wkhtmltopdf/0.12.6 Safari/534.34",
+		"Mozilla/5.0 (Unknown; Linux x86_64) AppleWebKit/534.34 (KHTML, like Gecko) wkhtmltopdf Safari/534.34",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) QtWebEngine/5.15.2 Chrome/83.0.4103.122 Safari/537.36",
+		"Prince/15.1 (www.princexml.com)",
+		"OpenAI/Python 1.3.0",
+		"Anthropic/Python 0.18.1",
+		"Anthropic/Python",
+	} {
+		agent := ua.Parse(s)
+		if !agent.Tool || agent.Bot {
+			t.Error("\n", s, "should be tool")
+		}
+	}
+
+	// Qt based browsers send QtWebEngine token too
+	const falkon = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Falkon/3.2.0 QtWebEngine/5.15.8 Chrome/87.0.4280.144 Safari/537.36"
+	if agent := ua.Parse(falkon); agent.Tool {
+		t.Error("\n", falkon, "should not be tool")
+	}
+}
+
+func TestHasVersion(t *testing.T) {
+	tests := []struct {
+		ua      string
+		version string
+		want    bool
+	}{
+		{"PostmanRuntime/7.36.0", "7.36.0", true},
+		{"Thunder Client (https://www.thunderclient.com)", "", false},
+		{"PostmanRuntime/dev", "dev", false},
+		{"SkypeUriPreview Preview/0.5", "0.5", true},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Version != test.version {
+			t.Error("\n", test.ua, "\nVersion should be", test.version, "not", agent.Version)
+		}
+		if got := agent.HasVersion(); got != test.want {
+			t.Error("\n", test.ua, "\nHasVersion should be", test.want, "not", got)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		ua       string
+		language string
+		region   string
+	}{
+		{"Mozilla/5.0 (Linux; U; Android 4.0.3; en-us; KFTT Build/IML74K) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "en", "US"},
+		{"Mozilla/5.0 (Linux; U; Android 4.4.2; pt-br; SM-G350M Build/KOT49H) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30", "pt", "BR"},
+		{"Mozilla/5.0 (iPhone; U; CPU iPhone OS 4_3_3 like Mac OS X; zh-Hant) AppleWebKit/533.17.9 (KHTML, like Gecko) Version/5.0.2 Mobile/8J2 Safari/6533.18.5", "zh", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "", ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Language != test.language {
+			t.Error("\n", test.ua, "\nLanguage should be", test.language, "not", agent.Language)
+		}
+		if agent.Region != test.region {
+			t.Error("\n", test.ua, "\nRegion should be", test.region, "not", agent.Region)
+		}
+	}
+}
+
+func TestParseWithHints(t *testing.T) {
+	const braveUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	tests := []struct {
+		ua      string
+		secCHUA string
+		name    string
+		version string
+	}{
+		{braveUA, `"Not_A Brand";v="8", "Chromium";v="120", "Brave";v="120"`, ua.Brave, "120.0.0.0"},
+		{braveUA, `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`, ua.Chrome, "120.0.0.0"},
+		{braveUA, "", ua.Chrome, "120.0.0.0"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", `"Brave";v="120"`, ua.Firefox, "121.0"},
+	}
+	for _, test := range tests {
+		agent := ua.ParseWithHints(test.ua, test.secCHUA)
+		if agent.Name != test.name {
+			t.Error("\n", test.ua, test.secCHUA, "\nName should be", test.name, "not", agent.Name)
+		}
+		if agent.Version != test.version {
+			t.Error("\n", test.ua, test.secCHUA, "\nVersion should be", test.version, "not", agent.Version)
+		}
+	}
+}
+
+func TestDeviceClass(t *testing.T) {
+	tests := []struct {
+		ua       string
+		class    string
+		emulated string
+	}{
+		{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.DeviceBot, ua.DeviceMobile},
+		{"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/120.0.6099.71 Safari/537.36", ua.DeviceBot, ua.DeviceDesktop},
+		{"Mozilla/5.0 (Linux; Android 5.0) AppleWebKit/537.36 (KHTML, like Gecko) Mobile Safari/537.36 (compatible; Bytespider; spider-feedback@bytedance.com)", ua.DeviceBot, ua.DeviceMobile},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/69.0.3497.81 YisouSpider/5.0 Safari/537.36", ua.DeviceBot, ua.DeviceDesktop},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", ua.DeviceMobile, ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.DeviceDesktop, ""},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if got := agent.DeviceClass(); got != test.class {
+			t.Error("\n", test.ua, "\nDeviceClass should be", test.class, "not", got)
+		}
+		if agent.EmulatedDevice != test.emulated {
+			t.Error("\n", test.ua, "\nEmulatedDevice should be", test.emulated, "not", agent.EmulatedDevice)
+		}
+	}
+}
+
+func TestDeviceType(t *testing.T) {
+	tests := []struct {
+		ua         string
+		deviceType ua.DeviceType
+	}{
+		{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ua.DeviceTypeBot},
+		{"Mozilla/5.0 (PlayStation; PlayStation 5/2.26) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0 Safari/605.1.15", ua.DeviceTypeConsole},
+		{"Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36", ua.DeviceTypeTV},
+		{"Mozilla/5.0 (iPad; CPU OS 10_3_2 like Mac OS X) AppleWebKit/603.2.4 (KHTML, like Gecko) Version/10.0 Mobile/14F89 Safari/602.1", ua.DeviceTypeTablet},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", ua.DeviceTypeMobile},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.DeviceTypeDesktop},
+		{"Mozilla/5.0 (Linux; Tizen 4.0; SAMSUNG SM-R800) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/1.0 Chrome/56.0.2924.0 Mobile Safari/537.36", ua.DeviceTypeWatch},
+		{"", ua.DeviceTypeUnknown},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if got := agent.DeviceType(); got != test.deviceType {
+			t.Error("\n", test.ua, "\nDeviceType should be", test.deviceType, "not", got)
+		}
+		if got := agent.DeviceClass(); got != string(test.deviceType) {
+			t.Error("\n", test.ua, "\nDeviceClass should be", test.deviceType, "not", got)
+		}
+	}
+
+	const chromebook = "Mozilla/5.0 (X11; CrOS x86_64 14150.74.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.114 Safari/537.36"
+	if got := ua.New(ua.WithChromeOSAsDesktop(false)).Parse(chromebook).DeviceType(); got != ua.DeviceTypeChromeOS {
+		t.Error("\n", chromebook, "\nDeviceType should be", ua.DeviceTypeChromeOS, "not", got)
+	}
+}
+
+func TestChromeOSFlex(t *testing.T) {
+	// ChromeOS Flex on generic hardware sends the same user agent as a Chromebook
+	const flex = "Mozilla/5.0 (X11; CrOS x86_64 15359.58.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.5735.350 Safari/537.36"
+	agent := ua.Parse(flex)
+	if agent.OS != ua.ChromeOS || agent.OSVersion != "15359.58.0" {
+		t.Error("\n", flex, "\nOS should be ChromeOS 15359.58.0 not", agent.OS, agent.OSVersion)
+	}
+	if agent.Architecture != ua.ArchAMD64 {
+		t.Error("\n", flex, "\nArchitecture should be", ua.ArchAMD64, "not", agent.Architecture)
+	}
+	if agent.Name != ua.Chrome || !agent.Desktop {
+		t.Error("\n", flex, "\nshould be desktop Chrome, got", agent.Name, agent.DeviceClass())
+	}
+}
+
+func TestWithChromeOSAsDesktop(t *testing.T) {
+	const chromebook = "Mozilla/5.0 (X11; CrOS x86_64 14150.74.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/94.0.4606.114 Safari/537.36"
+	tests := []struct {
+		desktop bool
+		class   string
+	}{
+		{true, ua.DeviceDesktop},
+		{false, ua.DeviceChromeOS},
+	}
+	for _, test := range tests {
+		agent := ua.New(ua.WithChromeOSAsDesktop(test.desktop)).Parse(chromebook)
+		if agent.Desktop != test.desktop {
+			t.Error("\n", test.desktop, "\nDesktop should be", test.desktop, "not", agent.Desktop)
+		}
+		if got := agent.DeviceClass(); got != test.class {
+			t.Error("\n", test.desktop, "\nDeviceClass should be", test.class, "not", got)
+		}
+	}
+
+	if got := ua.Parse(chromebook).DeviceClass(); got != ua.DeviceDesktop {
+		t.Error("\nDeviceClass should be desktop by default, not", got)
+	}
+}
+
+func TestBrowser64Bit(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", true},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", false},
+		{"Mozilla/5.0 (Windows NT 6.1; rv:52.0) Gecko/20100101 Firefox/52.0", false},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Browser64Bit != test.want {
+			t.Error("\n", test.ua, "\nBrowser64Bit should be", test.want, "not", agent.Browser64Bit)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	agent := ua.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36")
+	b, err := json.Marshal(agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"Chrome","version":"120.0.6099.109","version_no":"120.0.6099","os":"Windows","os_version":"10.0","os_version_no":"10.0.0","mobile":false,"tablet":false,"desktop":true,"bot":false,` +
+		`"string":"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36",` +
+		`"engine":"Blink","screen_class":"large","architecture":"amd64","browser_64bit":true}`
+	if string(b) != want {
+		t.Errorf("\nJSON should be\n%s\nnot\n%s", want, b)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	uas := make([]string, 0, len(testTable)+len(botTable))
+	for _, test := range testTable {
+		uas = append(uas, test[0])
+	}
+	for _, test := range botTable {
+		uas = append(uas, test.ua)
+	}
+	for _, s := range uas {
+		agent := ua.Parse(s)
+		b, err := json.Marshal(agent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got ua.UserAgent
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != agent {
+			t.Errorf("\n%s\nshould decode to\n%+v\nnot\n%+v", s, agent, got)
+		}
+	}
+}
+
+func TestFirefoxDevice(t *testing.T) {
+	tests := []struct {
+		ua      string
+		mobile  bool
+		tablet  bool
+		desktop bool
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", false, false, true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", false, false, true},
+		{"Mozilla/5.0 (Android 14; Mobile; rv:121.0) Gecko/121.0 Firefox/121.0", true, false, false},
+		{"Mozilla/5.0 (Android 14; rv:121.0) Gecko/121.0 Firefox/121.0", true, false, false},
+		{"Mozilla/5.0 (Android 13; Tablet; rv:121.0) Gecko/121.0 Firefox/121.0", false, true, false},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Name != ua.Firefox {
+			t.Error("\n", test.ua, "\nName should be Firefox, not", agent.Name)
+		}
+		if agent.Mobile != test.mobile {
+			t.Error("\n", test.ua, "\nMobile should be", test.mobile, "not", agent.Mobile)
+		}
+		if agent.Tablet != test.tablet {
+			t.Error("\n", test.ua, "\nTablet should be", test.tablet, "not", agent.Tablet)
+		}
+		if agent.Desktop != test.desktop {
+			t.Error("\n", test.ua, "\nDesktop should be", test.desktop, "not", agent.Desktop)
+		}
+	}
+}
+
+func TestVersionNoCompare(t *testing.T) {
+	tests := []struct {
+		a, b ua.VersionNo
+		want int
+	}{
+		{ua.VersionNo{Major: 15, Minor: 4, Patch: 1}, ua.VersionNo{Major: 15, Minor: 4, Patch: 1}, 0},
+		{ua.VersionNo{Major: 101}, ua.VersionNo{Major: 100, Minor: 9, Patch: 9}, 1},
+		{ua.VersionNo{Major: 15, Minor: 3, Patch: 9}, ua.VersionNo{Major: 15, Minor: 4}, -1},
+		{ua.VersionNo{Major: 15, Minor: 4}, ua.VersionNo{Major: 15, Minor: 4, Patch: 1}, -1},
+		{ua.VersionNo{}, ua.VersionNo{Major: 1}, -1},
+	}
+	for _, test := range tests {
+		if got := test.a.Compare(test.b); got != test.want {
+			t.Errorf("%+v.Compare(%+v) should be %d, not %d", test.a, test.b, test.want, got)
+		}
+		if got := test.a.Less(test.b); got != (test.want < 0) {
+			t.Errorf("%+v.Less(%+v) should be %t, not %t", test.a, test.b, test.want < 0, got)
+		}
+	}
+}
+
+func TestWithStrictVersion(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"PostmanRuntime/7.36.0", ua.Postman, "7.36.0"},
+		{"PostmanRuntime/dev", ua.Postman, ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:109.0) Gecko/20100101 Firefox/109.0b4", ua.Firefox, ""},
+	}
+	p := ua.New(ua.WithStrictVersion(true))
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name {
+			t.Error("\n", test.ua, "\nName should be", test.name, "not", agent.Name)
+		}
+		if agent.Version != test.version {
+			t.Error("\n", test.ua, "\nVersion should be", test.version, "not", agent.Version)
+		}
+		if agent.HasVersion() != (test.version != "") {
+			t.Error("\n", test.ua, "\nVersionNo should be zero for discarded version, got", agent.VersionNo)
+		}
+	}
+
+	if agent := ua.Parse("PostmanRuntime/dev"); agent.Version != "dev" {
+		t.Error("\nVersion should be kept by default, got", agent.Version)
+	}
+}
+
+func TestVersionNoString(t *testing.T) {
+	tests := []struct {
+		v    ua.VersionNo
+		want string
+	}{
+		{ua.VersionNo{Major: 15, Minor: 4, Patch: 1}, "15.4.1"},
+		{ua.VersionNo{Major: 15, Minor: 0, Patch: 1}, "15.0.1"},
+		{ua.VersionNo{Major: 15, Minor: 4}, "15.4"},
+		{ua.VersionNo{Major: 15}, "15"},
+		{ua.VersionNo{}, ""},
+	}
+	for _, test := range tests {
+		if got := test.v.String(); got != test.want {
+			t.Errorf("%d.%d.%d should be %q, not %q", test.v.Major, test.v.Minor, test.v.Patch, test.want, got)
+		}
+	}
+
+	var _ fmt.Stringer = ua.VersionNo{}
+}
+
+func TestVendor(t *testing.T) {
+	tests := []struct {
+		ua     string
+		vendor string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Google"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", "Apple"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91", "Microsoft"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0", "Mozilla"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0", "Opera"},
+		{"Mozilla/5.0 (Linux; Android 13; SAMSUNG SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36", "Samsung"},
+		{"PostmanRuntime/7.36.0", ""},
+	}
+	for _, test := range tests {
+		if got := ua.Parse(test.ua).Vendor(); got != test.vendor {
+			t.Error("\n", test.ua, "\nVendor should be", test.vendor, "not", got)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, test := range testTable {
+		want := ua.Parse(test[0])
+		if got := ua.ParseBytes([]byte(test[0])); got != want {
+			t.Errorf("\n%s\nParseBytes should be\n%+v\nnot\n%+v", test[0], want, got)
+		}
+	}
+}
+
+func TestWithOverride(t *testing.T) {
+	const pinned = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	p := ua.New(ua.WithOverride(pinned, ua.UserAgent{
+		Name:    ua.Brave,
+		Version: "1.61.109",
+		OS:      ua.Windows,
+		Desktop: true,
+	}))
+
+	agent := p.Parse(pinned)
+	if agent.Name != ua.Brave || agent.Version != "1.61.109" || agent.String != pinned {
+		t.Errorf("override should short-circuit parsing, got %+v", agent)
+	}
+	if os, _ := p.ParseOS(pinned); os != ua.Windows {
+		t.Error("ParseOS should use override, got", os)
+	}
+
+	agent = p.Parse(strings.Replace(pinned, "120.0.0.0", "121.0.0.0", 1))
+	if agent.Name != ua.Chrome {
+		t.Error("other user agents should be parsed, got", agent.Name)
+	}
+}
+
+func TestSameFamily(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			true,
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			false,
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			false,
+		},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		if got := ua.SameFamily(test.a, test.b); got != test.want {
+			t.Error("\n", test.a, "\n", test.b, "\nSameFamily should be", test.want, "not", got)
+		}
+	}
+}
+
+func TestWithHeadlessAsBot(t *testing.T) {
+	const headless = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.6099.109 Safari/537.36"
+	tests := []struct {
+		p   *ua.Parser
+		bot bool
+	}{
+		{ua.New(), true},
+		{ua.New(ua.WithHeadlessAsBot(true)), true},
+		{ua.New(ua.WithHeadlessAsBot(false)), false},
+	}
+	for _, test := range tests {
+		agent := test.p.Parse(headless)
+		if agent.Name != ua.HeadlessChrome {
+			t.Error("\nName should be", ua.HeadlessChrome, "not", agent.Name)
+		}
+		if agent.Bot != test.bot {
+			t.Error("\nBot should be", test.bot, "not", agent.Bot)
+		}
+	}
+}
+
+func TestAutomation(t *testing.T) {
+	tests := []struct {
+		ua         string
+		automation bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Selenium-WebDriver/4.16", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91 Microsoft-WebDriver/120.0.2210.91", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91", false},
+	}
+	for _, test := range tests {
+		if agent := ua.Parse(test.ua); agent.Automation != test.automation {
+			t.Error("\n", test.ua, "\nAutomation should be", test.automation, "not", agent.Automation)
+		}
+	}
+}
+
+func TestArchitecture(t *testing.T) {
+	tests := []struct {
+		ua        string
+		arch      string
+		osVersion string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.ArchAMD64, "10.0"},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", ua.ArchAMD64, "6.1"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", ua.ArchAMD64, ""},
+		{"Mozilla/5.0 (X11; Linux armv7l) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/89.0.4389.90 Safari/537.36", ua.ArchARM, ""},
+		{"Mozilla/5.0 (X11; Linux aarch64; rv:121.0) Gecko/20100101 Firefox/121.0", ua.ArchARM64, ""},
+		// Apple Silicon Macs pretend to be Intel
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", "", "10.15.7"},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.Architecture != test.arch {
+			t.Error("\n", test.ua, "\nArchitecture should be", test.arch, "not", agent.Architecture)
+		}
+		if agent.OSVersion != test.osVersion {
+			t.Error("\n", test.ua, "\nOSVersion should be", test.osVersion, "not", agent.OSVersion)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	uas := make([]string, 0, len(testTable)+len(botTable))
+	for _, test := range testTable {
+		uas = append(uas, test[0])
+	}
+	for _, test := range botTable {
+		uas = append(uas, test.ua)
+	}
+	for _, s := range uas {
+		got, want := ua.Tokenize(s), ua.ParseTokens(s)
+		if len(got) != len(want) {
+			t.Errorf("\n%s\nTokens should be\n%+v\nnot\n%+v", s, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("\n%s\nToken %d should be %+v, not %+v", s, i, want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestLinuxOSVersion(t *testing.T) {
+	tests := []struct {
+		ua        string
+		osVersion string
+		arch      string
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "", ua.ArchAMD64},
+		{"Mozilla/5.0 (X11; Linux i686; rv:109.0) Gecko/20100101 Firefox/121.0", "", ua.ArchX86},
+		{"Mozilla/5.0 (X11; U; Linux 2.6.32-5-amd64; en-US) AppleWebKit/534.10 (KHTML, like Gecko) Chrome/8.0.552.237 Safari/534.10", "2.6.32-5-amd64", ua.ArchAMD64},
+	}
+	for _, test := range tests {
+		agent := ua.Parse(test.ua)
+		if agent.OS != ua.Linux {
+			t.Error("\n", test.ua, "\nOS should be Linux not", agent.OS)
+		}
+		if agent.OSVersion != test.osVersion {
+			t.Error("\n", test.ua, "\nOSVersion should be", test.osVersion, "not", agent.OSVersion)
+		}
+		if agent.Architecture != test.arch {
+			t.Error("\n", test.ua, "\nArchitecture should be", test.arch, "not", agent.Architecture)
+		}
+	}
+}
+
+func TestWindowsName(t *testing.T) {
+	tests := []struct {
+		ua   string
+		name string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Windows 10"},
+		{"Mozilla/5.0 (Windows NT 6.3; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36", "Windows 8.1"},
+		{"Mozilla/5.0 (Windows NT 6.2; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36", "Windows 8"},
+		{"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36", "Windows 7"},
+		{"Mozilla/5.0 (Windows NT 6.0; rv:52.0) Gecko/20100101 Firefox/52.0", "Windows Vista"},
+		{"Mozilla/5.0 (Windows NT 5.1; rv:52.0) Gecko/20100101 Firefox/52.0", "Windows XP"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", ""},
+	}
+	for _, test := range tests {
+		if got := ua.Parse(test.ua).WindowsName(); got != test.name {
+			t.Error("\n", test.ua, "\nWindowsName should be", test.name, "not", got)
+		}
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	const chrome = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", chrome)
+	agent := ua.FromRequest(r)
+	if agent.Name != ua.Chrome || agent.String != chrome {
+		t.Errorf("\n%s\nshould be parsed as Chrome, got %+v", chrome, agent)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	if agent := ua.FromRequest(r); agent != (ua.UserAgent{}) {
+		t.Errorf("request without User-Agent should give zero UserAgent, got %+v", agent)
+	}
+}
+
+func TestWithRequireMozillaPrefix(t *testing.T) {
+	tests := []struct {
+		ua   string
+		name string
+		tool bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, false},
+		{"Chrome/120.0.0.0 Safari/537.36", ua.Chrome, true},
+		{"Opera/9.80 (Windows NT 6.1; WOW64) Presto/2.12.388 Version/12.18", ua.Opera, false},
+	}
+	p := ua.New(ua.WithRequireMozillaPrefix(true))
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name {
+			t.Error("\n", test.ua, "\nName should be", test.name, "not", agent.Name)
+		}
+		if agent.Tool != test.tool {
+			t.Error("\n", test.ua, "\nTool should be", test.tool, "not", agent.Tool)
+		}
+	}
+
+	if agent := ua.Parse("Chrome/120.0.0.0 Safari/537.36"); agent.Tool {
+		t.Error("\nbrowser without Mozilla prefix should not be tool by default")
+	}
+}
+
+func TestWithEngineVersionFallback(t *testing.T) {
+	tests := []struct {
+		ua      string
+		name    string
+		version string
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome", ua.Chrome, "537.36"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Safari", ua.Safari, "605.1.15"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox", ua.Firefox, "109.0"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/", ua.Firefox, "109.0"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", ua.Chrome, "120.0.0.0"},
+	}
+	p := ua.New(ua.WithEngineVersionFallback(true))
+	for _, test := range tests {
+		agent := p.Parse(test.ua)
+		if agent.Name != test.name || agent.Version != test.version {
+			t.Error("\n", test.ua, "\nshould be", test.name, test.version, "not", agent.Name, agent.Version)
+		}
+	}
+
+	if agent := ua.Parse(tests[0].ua); agent.Version != "" {
+		t.Error("\nversion should not fall back to engine version by default, got", agent.Version)
+	}
+}
+
+func TestWithBotPatterns(t *testing.T) {
+	tests := []struct {
+		ua  string
+		bot bool
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 acme-indexer/2.1", true},
+		{"ACME-Indexer", true},
+		{"Mozilla/5.0 (compatible; LinkChecker/1.0)", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", false},
+	}
+	p := ua.New(ua.WithBotPatterns([]string{"acme-indexer"}), ua.WithBotPatterns([]string{"linkchecker"}))
+	for _, test := range tests {
+		if agent := p.Parse(test.ua); agent.Bot != test.bot {
+			t.Error("\n", test.ua, "\nBot should be", test.bot, "not", agent.Bot)
+		}
+		if bot := p.IsBotUA(test.ua); bot != test.bot {
+			t.Error("\n", test.ua, "\nIsBotUA should be", test.bot, "not", bot)
+		}
+	}
+
+	if agent := ua.Parse(tests[0].ua); agent.Bot {
+		t.Error("\n", tests[0].ua, "\nshould not be bot without registered pattern")
+	}
+}
+
+func TestParserWithBotPatterns(t *testing.T) {
+	const s = "Mozilla/5.0 (compatible; acme-indexer/2.1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	p := ua.New(ua.WithStrictVersion(true))
+	q := p.WithBotPatterns([]string{"acme-indexer"})
+	if agent := q.Parse(s); !agent.Bot {
+		t.Error("\n", s, "\nshould be bot with registered pattern")
+	}
+	if agent := q.Parse("Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/109.0b4"); agent.Version != "" {
+		t.Error("\nparser built from strict parser should keep strict version, got", agent.Version)
+	}
+	if agent := p.Parse(s); agent.Bot {
+		t.Error("\n", s, "\nshould not be bot with the original parser")
+	}
+}
+
+func TestParseClientHints(t *testing.T) {
+	tests := []struct {
+		ua      string
+		hints   ua.ClientHints
+		name    string
+		version string
+		mobile  bool
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			ua.ClientHints{
+				UA:              `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`,
+				FullVersionList: `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.109", "Google Chrome";v="120.0.6099.109"`,
+				Mobile:          "?0",
+				Platform:        `"Windows"`,
+			},
+			ua.Chrome, "120.0.6099.109", false,
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			ua.ClientHints{
+				FullVersionList: `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.144", "Google Chrome";v="120.0.6099.144"`,
+				Mobile:          "?1",
+			},
+			ua.Chrome, "120.0.6099.144", true,
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			ua.ClientHints{
+				UA:              `"Not_A Brand";v="8", "Chromium";v="120", "Microsoft Edge";v="120"`,
+				FullVersionList: `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.130", "Microsoft Edge";v="120.0.2210.91"`,
+			},
+			ua.Edge, "120.0.2210.91", false,
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			ua.ClientHints{},
+			ua.Chrome, "120.0.0.0", false,
+		},
+	}
+	for _, test := range tests {
+		agent := ua.ParseClientHints(test.ua, test.hints)
+		if agent.Name != test.name {
+			t.Error("\n", test.ua, "\nName should be", test.name, "not", agent.Name)
+		}
+		if agent.Version != test.version {
+			t.Error("\n", test.ua, "\nVersion should be", test.version, "not", agent.Version)
+		}
+		if agent.VersionNoFull() != test.version[:strings.LastIndexByte(test.version, '.')] {
+			t.Error("\n", test.ua, "\nVersionNo should follow version, got", agent.VersionNoFull())
+		}
+		if agent.Mobile != test.mobile {
+			t.Error("\n", test.ua, "\nMobile should be", test.mobile, "not", agent.Mobile)
+		}
+	}
+}
+
+func TestParseClientHintsUnknownBrowser(t *testing.T) {
+	hints := ua.ClientHints{
+		FullVersionList: `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.109", "Google Chrome";v="120.0.6099.109"`,
+	}
+	for _, s := range []string{"", "Mozilla/5.0"} {
+		agent := ua.ParseClientHints(s, hints)
+		if agent.Version != "" {
+			t.Errorf("\n%q\nVersion should be empty, not %s", s, agent.Version)
+		}
+	}
+}
+
+func TestParseClientHintsStrictVersion(t *testing.T) {
+	s := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	hints := ua.ClientHints{
+		UA:              `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`,
+		FullVersionList: `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.109-beta", "Google Chrome";v="120.0.6099.109-beta"`,
+	}
+	agent := ua.New(ua.WithStrictVersion(true)).ParseClientHints(s, hints)
+	if agent.Version != "120.0.0.0" {
+		t.Error("\n", s, "\nVersion should be 120.0.0.0, not", agent.Version)
+	}
+}
+
+func TestParseClientHintsMobile(t *testing.T) {
+	tests := []struct {
+		ua          string
+		mobile      string
+		deviceClass string
+		screenClass string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "?1", ua.DeviceMobile, ua.ScreenSmall},
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "?0", ua.DeviceDesktop, ua.ScreenLarge},
+	}
+	for _, test := range tests {
+		agent := ua.ParseClientHints(test.ua, ua.ClientHints{Mobile: test.mobile})
+		if got := agent.DeviceClass(); got != test.deviceClass {
+			t.Error("\n", test.ua, "\nDeviceClass with", test.mobile, "should be", test.deviceClass, "not", got)
+		}
+		if agent.ScreenClass != test.screenClass {
+			t.Error("\n", test.ua, "\nScreenClass with", test.mobile, "should be", test.screenClass, "not", agent.ScreenClass)
+		}
+		if n := btoi(agent.Mobile) + btoi(agent.Tablet) + btoi(agent.Desktop); n != 1 {
+			t.Errorf("\n%s\nexactly one form factor should be set, got mobile %v tablet %v desktop %v", test.ua, agent.Mobile, agent.Tablet, agent.Desktop)
+		}
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestParseInto(t *testing.T) {
+	p := ua.New()
+	var agent ua.UserAgent
+	for _, test := range testTable {
+		p.ParseInto(test[0], &agent)
+		if want := p.Parse(test[0]); agent != want {
+			t.Errorf("\n%s\nParseInto should be\n%+v\nnot\n%+v", test[0], want, agent)
+		}
+	}
+}
+
+func TestIsBotUA(t *testing.T) {
+	uas := []string{
+		"curl/8.4.0",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36",
+	}
+	for _, test := range testTable {
+		uas = append(uas, test[0])
+	}
+	for _, test := range botTable {
+		uas = append(uas, test.ua)
+	}
+	p := ua.New(ua.WithHeadlessAsBot(false))
+	for _, s := range uas {
+		if got, want := ua.IsBotUA(s), ua.Parse(s).Bot; got != want {
+			t.Error("\n", s, "\nIsBotUA should be", want, "not", got)
+		}
+		if got, want := p.IsBotUA(s), p.Parse(s).Bot; got != want {
+			t.Error("\n", s, "\nIsBotUA without headless bots should be", want, "not", got)
+		}
+	}
+}
+
 func TestSingle(t *testing.T) {
 	agent := ua.Parse("SonyEricssonK310iv/R4DA Browser/NetFront/3.3 Profile/MIDP-2.0 Configuration/CLDC-1.1 UP.Link/6.3.1.13.0")
 	fmt.Printf("\n%+v\n", agent)
@@ -225,6 +1813,99 @@ func BenchmarkUserAgent(b *testing.B) {
 	}
 }
 
+func BenchmarkParseOS(b *testing.B) {
+	b.Run("Parse", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, test := range testTable {
+				testUA = ua.Parse(test[0])
+			}
+		}
+	})
+	b.Run("ParseOS", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, test := range testTable {
+				testUA.OS, testUA.OSVersion = ua.ParseOS(test[0])
+			}
+		}
+	})
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	uas := make([][]byte, len(testTable))
+	for i, test := range testTable {
+		uas[i] = []byte(test[0])
+	}
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, userAgent := range uas {
+				testUA = ua.Parse(string(userAgent))
+			}
+		}
+	})
+	b.Run("ParseBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, userAgent := range uas {
+				testUA = ua.ParseBytes(userAgent)
+			}
+		}
+	})
+}
+
+func BenchmarkParseInto(b *testing.B) {
+	p := ua.New()
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, test := range testTable {
+				testUA = p.Parse(test[0])
+			}
+		}
+	})
+	b.Run("ParseInto", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, test := range testTable {
+				p.ParseInto(test[0], &testUA)
+			}
+		}
+	})
+}
+
+func BenchmarkIsBotUA(b *testing.B) {
+	p := ua.New()
+	var bot bool
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, test := range testTable {
+				bot = p.Parse(test[0]).Bot
+			}
+		}
+	})
+	b.Run("IsBotUA", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, test := range testTable {
+				bot = p.IsBotUA(test[0])
+			}
+		}
+	})
+	_ = bot
+}
+
+func BenchmarkAcquire(b *testing.B) {
+	p := ua.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, test := range testTable {
+			agent := p.Acquire(test[0])
+			p.Release(agent)
+		}
+	}
+}
+
 func ExampleParse() {
 	userAgents := []string{
 		// Mac
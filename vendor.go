@@ -0,0 +1,31 @@
+package useragent
+
+// browserVendors maps browser names to companies behind them.
+var browserVendors = map[string]string{
+	Chrome:           "Google",
+	HeadlessChrome:   "Google",
+	Safari:           "Apple",
+	Edge:             "Microsoft",
+	InternetExplorer: "Microsoft",
+	Firefox:          "Mozilla",
+	Opera:            "Opera",
+	OperaMini:        "Opera",
+	OperaTouch:       "Opera",
+	OperaGX:          "Opera",
+	OperaNeon:        "Opera",
+	OperaCrypto:      "Opera",
+	SamsungBrowser:   "Samsung",
+	Vivaldi:          "Vivaldi",
+	Brave:            "Brave",
+	YandexBrowser:    "Yandex",
+	Whale:            "Naver",
+	UCBrowser:        "UCWeb",
+	SogouBrowser:     "Sogou",
+	Netscape:         "Netscape",
+}
+
+// Vendor returns the company behind the detected browser, e.g., Google for Chrome.
+// Empty string is returned for unknown browsers.
+func (ua UserAgent) Vendor() string {
+	return browserVendors[ua.Name]
+}
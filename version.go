@@ -13,23 +13,149 @@ type VersionNo struct {
 }
 
 func parseVersion(ver string, verno *VersionNo) {
-	var err error
-	parts := strings.Split(ver, ".")
-	if len(parts) > 0 {
-		if verno.Major, err = strconv.Atoi(parts[0]); err != nil {
-			return
+	for _, dst := range [...]*int{&verno.Major, &verno.Minor, &verno.Patch} {
+		part := ver
+		j := strings.IndexByte(ver, '.')
+		if j != -1 {
+			part, ver = ver[:j], ver[j+1:]
 		}
-	}
-	if len(parts) > 1 {
-		if verno.Minor, err = strconv.Atoi(parts[1]); err != nil {
+		n, ok := atoi(part)
+		*dst = n
+		if !ok || j == -1 {
 			return
 		}
-		if len(parts) > 2 {
-			if verno.Patch, err = strconv.Atoi(parts[2]); err != nil {
-				return
+	}
+}
+
+// hasNumericMajor returns true if ver starts with a decimal major version, e.g., 109.0b4 but not dev.
+func hasNumericMajor(ver string) bool {
+	if j := strings.IndexByte(ver, '.'); j != -1 {
+		ver = ver[:j]
+	}
+	_, ok := atoi(ver)
+	return ok
+}
+
+// isNumericVersion returns true if ver consists of dot-separated decimal numbers, e.g., 59.0.3071.115.
+func isNumericVersion(ver string) bool {
+	for len(ver) > 0 {
+		part := ver
+		j := strings.IndexByte(ver, '.')
+		if j != -1 {
+			part, ver = ver[:j], ver[j+1:]
+			if ver == "" {
+				return false
 			}
+		} else {
+			ver = ""
+		}
+		if _, ok := atoi(part); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// maxInt is the largest int value, Go 1.14 has no math.MaxInt.
+const maxInt = int(^uint(0) >> 1)
+
+// atoi converts decimal digits to int without allocating an error on failure.
+// Numbers which don't fit into int are rejected.
+func atoi(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		d := int(s[i] - '0')
+		if n > (maxInt-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	return n, true
+}
+
+// String returns version in format <Major>.<Minor>.<Patch> omitting trailing zero components,
+// e.g., 15 or 15.4. Empty string is returned for zero version.
+func (v VersionNo) String() string {
+	switch {
+	case v.Patch != 0:
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	case v.Minor != 0:
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	case v.Major != 0:
+		return strconv.Itoa(v.Major)
+	}
+	return ""
+}
+
+// Compare returns -1 if v is lower than other, 1 if it is greater and 0 if they are equal.
+// Versions are compared by Major, then Minor, then Patch.
+func (v VersionNo) Compare(other VersionNo) int {
+	switch {
+	case v.Major != other.Major:
+		return cmpInt(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return cmpInt(v.Minor, other.Minor)
+	default:
+		return cmpInt(v.Patch, other.Patch)
+	}
+}
+
+// Less returns true if v is lower than other, e.g., ua.VersionNo.Less(VersionNo{Major: 100}).
+func (v VersionNo) Less(other VersionNo) bool {
+	return v.Compare(other) < 0
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// formatVersion returns version string with exactly n components,
+// e.g., <Major>.<Minor>.<Patch> when n is 3.
+// Components missing in VersionNo are filled with zeros.
+func formatVersion(v VersionNo, n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		switch i {
+		case 0:
+			parts[i] = strconv.Itoa(v.Major)
+		case 1:
+			parts[i] = strconv.Itoa(v.Minor)
+		case 2:
+			parts[i] = strconv.Itoa(v.Patch)
+		default:
+			parts[i] = "0"
 		}
 	}
+	return strings.Join(parts, ".")
+}
+
+// isBelowMin returns true if v is lower than the minimum version of name in min.
+// Unknown names and zero versions are never below the minimum.
+func isBelowMin(min map[string]VersionNo, name string, v VersionNo) bool {
+	m, ok := min[name]
+	if !ok || v == (VersionNo{}) {
+		return false
+	}
+	return v.Less(m)
+}
+
+// HasVersion returns true if a numeric version was parsed from Version.
+// It is false when no version was sent or it couldn't be parsed, e.g. PostmanRuntime/dev,
+// while 0.x versions such as Preview/0.5 count as parsed.
+func (ua UserAgent) HasVersion() bool {
+	return hasNumericMajor(ua.Version)
 }
 
 // VersionNoShort return version string in format <Major>.<Minor>